@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+	"github.com/denisdubovitskiy/feedparser/internal/fetcher"
+	"github.com/denisdubovitskiy/feedparser/internal/parsing"
+)
+
+const discoveryTimeout = 10 * time.Second
+
+// discoverFeed ищет ленту источника: сначала среди ссылок, объявленных
+// на странице, затем по типовым путям. Каждая найденная лента
+// загружается, чтобы убедиться, что её формат поддерживается.
+func discoverFeed(ctx context.Context, f *fetcher.HTTPFetcher, pageURL string) (parsing.FeedLink, bool) {
+	var candidates []string
+
+	body, err := fetch(ctx, f, pageURL)
+	if err != nil {
+		slog.Warn(fmt.Sprintf("discovery: unable to fetch %s: %v", pageURL, err))
+	} else {
+		links, err := parsing.FindFeedLinks(pageURL, body)
+		if err != nil {
+			slog.Warn(fmt.Sprintf("discovery: unable to parse %s: %v", pageURL, err))
+		}
+		for _, link := range links {
+			candidates = append(candidates, link.URL)
+		}
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		slog.Warn(fmt.Sprintf("discovery: invalid url %s: %v", pageURL, err))
+		return parsing.FeedLink{}, false
+	}
+
+	for _, path := range parsing.WellKnownFeedPaths {
+		ref, err := url.Parse(path)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, base.ResolveReference(ref).String())
+	}
+
+	checked := make(map[string]struct{}, len(candidates))
+
+	for _, candidate := range candidates {
+		if _, ok := checked[candidate]; ok {
+			continue
+		}
+		checked[candidate] = struct{}{}
+
+		body, err := fetch(ctx, f, candidate)
+		if err != nil {
+			continue
+		}
+
+		feedType, err := parsing.DetectFeedType(body)
+		if err != nil {
+			continue
+		}
+
+		return parsing.FeedLink{URL: candidate, Type: feedType}, true
+	}
+
+	return parsing.FeedLink{}, false
+}
+
+func fetch(ctx context.Context, f *fetcher.HTTPFetcher, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	return f.Fetch(ctx, url)
+}
+
+// feedKeys настройки источника, которые сохраняются при переводе на ленту.
+// Селекторы и настройки браузера лентам не нужны.
+var feedKeys = map[string]struct{}{
+	"enrich":    {},
+	"include":   {},
+	"exclude":   {},
+	"url_rules": {},
+	"rewrite":   {},
+	"tags":      {},
+	"channels":  {},
+}
+
+// discoverFeeds печатает отчёт об источниках с селекторами, которые можно
+// перевести на найденные ленты, и готовые для вставки в файл конфигурации
+// описания этих источников. Конфигурация не изменяется.
+func discoverFeeds(ctx context.Context, f *fetcher.HTTPFetcher, content []byte, sources []config.Source) error {
+	nodes, err := sourceNodes(content)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes) != len(sources) {
+		return fmt.Errorf("discovery: found %d sources in yaml, expected %d", len(nodes), len(sources))
+	}
+
+	var (
+		switchable []string
+		snippets   []string
+	)
+
+	for i, source := range sources {
+		if !source.Config.IsHTML() {
+			continue
+		}
+
+		slog.Info(fmt.Sprintf(`discovery: source "%s" looking for a feed`, source.Name))
+
+		link, ok := discoverFeed(ctx, f, source.URL)
+		if !ok {
+			slog.Info(fmt.Sprintf(`discovery: source "%s" has no feed`, source.Name))
+			continue
+		}
+
+		snippet, err := feedSnippet(nodes[i], link)
+		if err != nil {
+			return fmt.Errorf(`discovery: source "%s": %v`, source.Name, err)
+		}
+
+		switchable = append(switchable, fmt.Sprintf("%s: %s (%s)", source.Name, link.URL, link.Type))
+		snippets = append(snippets, snippet)
+	}
+
+	fmt.Printf("Sources that can be switched to feeds: %d\n", len(switchable))
+	for _, line := range switchable {
+		fmt.Printf("  %s\n", line)
+	}
+
+	if len(snippets) > 0 {
+		fmt.Println("\nReplace these sources in the config file:")
+		fmt.Print(strings.Join(snippets, ""))
+	}
+
+	return nil
+}
+
+// sourceNodes возвращает описания источников из файла конфигурации
+// в том порядке, в котором они записаны.
+func sourceNodes(content []byte) ([]*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("discovery: unable to parse yaml: %v", err)
+	}
+
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	doc := root.Content[0]
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == "sources" {
+			return doc.Content[i+1].Content, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// feedSnippet возвращает описание источника, переведённого на ленту,
+// в виде элемента списка sources. Значения настроек, нужных ленте,
+// переносятся из файла без изменений.
+func feedSnippet(source *yaml.Node, link parsing.FeedLink) (string, error) {
+	feedConfig := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			scalarNode("type"), scalarNode(link.Type),
+			scalarNode("feed_url"), scalarNode(link.URL),
+		},
+	}
+
+	switched := &yaml.Node{Kind: yaml.MappingNode}
+
+	for i := 0; i+1 < len(source.Content); i += 2 {
+		key, value := source.Content[i], source.Content[i+1]
+
+		if key.Value != "config" {
+			switched.Content = append(switched.Content, key, value)
+			continue
+		}
+
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			if _, ok := feedKeys[value.Content[j].Value]; ok {
+				feedConfig.Content = append(feedConfig.Content, value.Content[j], value.Content[j+1])
+			}
+		}
+
+		switched.Content = append(switched.Content, key, feedConfig)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	list := &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{switched}}
+	if err := encoder.Encode(list); err != nil {
+		return "", fmt.Errorf("unable to encode yaml: %v", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("unable to encode yaml: %v", err)
+	}
+
+	// Отступ элемента списка sources.
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if len(line) > 0 {
+			lines[i] = "  " + line
+		}
+	}
+
+	return strings.Join(lines, ""), nil
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
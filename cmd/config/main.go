@@ -7,21 +7,25 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
 
 	"github.com/denisdubovitskiy/feedparser/internal/config"
 	"github.com/denisdubovitskiy/feedparser/internal/database"
+	"github.com/denisdubovitskiy/feedparser/internal/fetcher"
 )
 
 var (
 	configFilename string
 	databasePath   string
+	discover       bool
 )
 
 func init() {
 	flag.StringVar(&configFilename, "config", "", "config file path")
 	flag.StringVar(&databasePath, "database", "", "database file path")
+	flag.BoolVar(&discover, "discover-feeds", false, "report sources with selectors which can be switched to feeds and print their yaml")
 	flag.Parse()
 }
 
@@ -36,6 +40,18 @@ func main() {
 		log.Fatalf("config: unable to parse config file: %v", err)
 	}
 
+	if discover {
+		httpFetcher := fetcher.NewHTTPFetcher(http.DefaultClient)
+		if err := discoverFeeds(context.Background(), httpFetcher, configContent, conf.Sources); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	if err := validateSources(conf.Sources); err != nil {
+		log.Fatalf("config: invalid config file:\n%v", err)
+	}
+
 	if err := os.MkdirAll(filepath.Dir(databasePath), os.ModePerm); err != nil {
 		log.Fatalf("config: unable to create a directory for a database: %v", err)
 	}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/denisdubovitskiy/feedparser/internal/browser"
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+	"github.com/denisdubovitskiy/feedparser/internal/parsing"
+)
+
+// validateSources проверяет селекторы и фильтры источников до записи в базу,
+// чтобы ошибки в селекторах обнаруживались при импорте, а не при обходе.
+func validateSources(sources []config.Source) error {
+	var errs []error
+
+	for _, source := range sources {
+		// Источник неизвестного типа обрабатывался бы как страница с селекторами.
+		if !source.Config.IsKnownType() {
+			errs = append(errs, fmt.Errorf(`source "%s": unknown type %q`, source.Name, source.Config.Type))
+			continue
+		}
+
+		if _, err := parsing.NewFilter(source.Config.Include, source.Config.Exclude); err != nil {
+			errs = append(errs, fmt.Errorf(`source "%s": %v`, source.Name, err))
+		}
+
+		if _, err := browser.NewOptions(source.Config); err != nil {
+			errs = append(errs, fmt.Errorf(`source "%s": %v`, source.Name, err))
+		}
+
+		parserSource := parsing.NewSource(source.Name, source.URL, source.Config)
+
+		if source.Config.IsFeed() {
+			if err := parserSource.ValidateRewrite(); err != nil {
+				errs = append(errs, fmt.Errorf(`source "%s": %v`, source.Name, err))
+			}
+			continue
+		}
+
+		if err := parserSource.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf(`source "%s": %v`, source.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
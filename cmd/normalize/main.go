@@ -0,0 +1,47 @@
+// Команда normalize однократно приводит адреса сохранённых статей
+// к каноническому виду по правилам их источников и объединяет повторы.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+	"github.com/denisdubovitskiy/feedparser/internal/database"
+	"github.com/denisdubovitskiy/feedparser/internal/parsing"
+)
+
+var (
+	databasePath string
+	dryRun       bool
+)
+
+func init() {
+	flag.StringVar(&databasePath, "database", "", "database file path")
+	flag.BoolVar(&dryRun, "dry-run", false, "report changes without saving them")
+	flag.Parse()
+}
+
+func main() {
+	db, err := database.New(databasePath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	service := database.NewService(db)
+
+	result, err := service.NormalizeArticleURLs(context.Background(), func(conf config.SourceConfig, url string) string {
+		return parsing.NormalizeURL(url, conf.URLRules)
+	}, dryRun)
+	if err != nil {
+		log.Fatalf("normalize: %v", err)
+	}
+
+	if dryRun {
+		log.Printf("normalize: dry run, %d articles would be updated, %d duplicates merged", result.Updated, result.Merged)
+		return
+	}
+
+	log.Printf("normalize: %d articles updated, %d duplicates merged", result.Updated, result.Merged)
+}
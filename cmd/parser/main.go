@@ -7,14 +7,18 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/denisdubovitskiy/feedparser/internal/browser"
+	"github.com/denisdubovitskiy/feedparser/internal/config"
 	"github.com/denisdubovitskiy/feedparser/internal/database"
+	"github.com/denisdubovitskiy/feedparser/internal/fetcher"
 	"github.com/denisdubovitskiy/feedparser/internal/parsing"
 	"github.com/denisdubovitskiy/feedparser/internal/task"
 	"github.com/denisdubovitskiy/feedparser/internal/telegram"
@@ -39,7 +43,13 @@ var (
 	confBrowserLocation    = os.Getenv("CRAWLER_BROWSER_LOCATION")
 	confCrawlInterval      = env("CRAWLER_CRAWL_INTERVAL", "5m0s")
 	confSendInterval       = env("CRAWLER_SEND_INTERVAL", "5m0s")
+	confSendMaxAge         = env("CRAWLER_SEND_MAX_AGE", "0s")
 	confIsPublisherEnabled = env("CRAWLER_PUBLISHER_ENABLED", "false") == "true"
+	confAdminChat          = os.Getenv("CRAWLER_TG_ADMIN_CHAT")
+	confBrokenAfter        = env("CRAWLER_BROKEN_AFTER", "3")
+	confBrokenDropRatio    = env("CRAWLER_BROKEN_DROP_RATIO", "0.5")
+	confBrowserTabs        = env("CRAWLER_BROWSER_TABS", "1")
+	confBrowserTabMaxUses  = env("CRAWLER_BROWSER_TAB_MAX_USES", "50")
 )
 
 func env(key, defaultValue string) string {
@@ -57,7 +67,13 @@ func main() {
 	fmt.Println("CRAWLER_BROWSER_LOCATION", confBrowserLocation)
 	fmt.Println("CRAWLER_CRAWL_INTERVAL", confCrawlInterval)
 	fmt.Println("CRAWLER_SEND_INTERVAL", confSendInterval)
+	fmt.Println("CRAWLER_SEND_MAX_AGE", confSendMaxAge)
 	fmt.Println("CRAWLER_PUBLISHER_ENABLED", confIsPublisherEnabled)
+	fmt.Println("CRAWLER_TG_ADMIN_CHAT", confAdminChat)
+	fmt.Println("CRAWLER_BROKEN_AFTER", confBrokenAfter)
+	fmt.Println("CRAWLER_BROKEN_DROP_RATIO", confBrokenDropRatio)
+	fmt.Println("CRAWLER_BROWSER_TABS", confBrowserTabs)
+	fmt.Println("CRAWLER_BROWSER_TAB_MAX_USES", confBrowserTabMaxUses)
 
 	appCtx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
@@ -69,22 +85,52 @@ func main() {
 
 	service := database.NewService(db)
 
-	var browserCtx context.Context
-	var browserCtxCancel context.CancelFunc
+	// Подключение к браузеру восстанавливается в фоне, если он перезапустился.
+	chrome := browser.NewLocalBrowser()
 	if confBrowserLocation == "remote" {
-		browserCtx, browserCtxCancel = browser.NewRemoteContext(confBrowserURL)
-		defer browserCtxCancel()
-	} else {
-		browserCtx, browserCtxCancel = browser.NewLocalContext()
-		defer browserCtxCancel()
+		chrome = browser.NewRemoteBrowser(confBrowserURL)
 	}
 
-	if err := browser.Run(browserCtx); err != nil {
+	if err := chrome.Start(appCtx); err != nil {
 		log.Fatal(err)
 	}
+	defer chrome.Close()
+
+	browserTabs, err := strconv.Atoi(confBrowserTabs)
+	if err != nil {
+		log.Fatalf("crawler: unable to parse browser tabs %s: %v", confBrowserTabs, err)
+	}
+
+	browserTabMaxUses, err := strconv.Atoi(confBrowserTabMaxUses)
+	if err != nil {
+		log.Fatalf("crawler: unable to parse browser tab max uses %s: %v", confBrowserTabMaxUses, err)
+	}
+
+	// Источники обрабатываются одновременно, каждому - своя вкладка браузера.
+	browserPool := browser.NewPool(chrome, browserTabs, browserTabMaxUses)
+	defer browserPool.Close()
 
 	parser := parsing.NewParser()
-	runner := task.NewRunner(service, 3)
+	httpFetcher := fetcher.NewHTTPFetcher(http.DefaultClient)
+	browserFetcher := browser.NewFetcher(browserPool)
+
+	// pageFetcher выбирает способ загрузки страниц источника: браузер
+	// нужен только страницам, которые строятся скриптами. Время загрузки
+	// страницы в браузере увеличивается на время ожидания условий готовности
+	// и выполнения действий на странице.
+	pageFetcher := func(source *database.Source) (fetcher.Fetcher, time.Duration, error) {
+		if !source.Config.IsRendered() {
+			return httpFetcher, pageTimeout, nil
+		}
+
+		opts, err := browser.NewOptions(source.Config)
+		if err != nil {
+			return nil, 0, fmt.Errorf("source: %s invalid browser options: %v", source.String(), err)
+		}
+
+		return browserFetcher.WithOptions(opts), pageTimeout + opts.Duration(), nil
+	}
+	runner := task.NewRunner(service, sourceRetries, browserPool.Size())
 
 	crawlInterval, err := time.ParseDuration(confCrawlInterval)
 	if err != nil {
@@ -93,39 +139,388 @@ func main() {
 
 	crawlTicker := time.NewTicker(crawlInterval)
 
+	brokenAfter, err := strconv.Atoi(confBrokenAfter)
+	if err != nil {
+		log.Fatalf("crawler: unable to parse broken after %s: %v", confBrokenAfter, err)
+	}
+
+	brokenDropRatio, err := strconv.ParseFloat(confBrokenDropRatio, 64)
+	if err != nil {
+		log.Fatalf("crawler: unable to parse broken drop ratio %s: %v", confBrokenDropRatio, err)
+	}
+
+	breakageRules := task.BreakageRules{After: brokenAfter, DropRatio: brokenDropRatio, Attempts: sourceRetries}
+
+	// Уведомления о сломанных источниках отправляются, только если задан чат администратора.
+	var alerter *telegram.Publisher
+	if len(confAdminChat) > 0 {
+		alerter = telegram.NewPublisher(confToken, confDefaultChannel)
+	}
+
+	// saveArticles сохраняет статьи, прошедшие фильтры источника,
+	// и возвращает количество ранее не встречавшихся статей, включая
+	// отброшенные. Отброшенные статьи сохраняются отдельно вместе с причиной.
+	saveArticles := func(source *database.Source, filter *parsing.Filter, articles []parsing.Article) int {
+		var saved int
+
+		for _, article := range articles {
+			if ok, reason := filter.Match(article); !ok {
+				log.Printf("source: %s %s filtered: %s", source.String(), article.String(), reason)
+
+				isNew, saveFilteredErr := service.SaveFilteredArticle(context.Background(), database.SaveFilteredArticleParams{
+					SourceID: source.ID,
+					Title:    article.Title,
+					Url:      article.DetailURL,
+					Reason:   reason,
+					Added:    unix.TimeNow(),
+				})
+				if saveFilteredErr != nil {
+					log.Printf("source: %s unable to save filtered: %v", article.String(), saveFilteredErr)
+					continue
+				}
+
+				if isNew {
+					saved++
+				}
+				continue
+			}
+
+			if source.Config.Enrich && !article.Enriched {
+				article = enrichArticle(appCtx, service, httpFetcher, parser, source, article)
+			}
+
+			isNew, saveArticleErr := service.SaveArticle(context.Background(), database.SaveArticleParams{
+				SourceID:     source.ID,
+				Title:        article.Title,
+				Url:          article.DetailURL,
+				Added:        unix.TimeNow(),
+				Published:    unix.FromTime(article.Published),
+				Author:       article.Author,
+				Summary:      article.Summary,
+				Image:        article.ImageURL,
+				CanonicalUrl: article.CanonicalURL,
+			})
+			if saveArticleErr != nil {
+				log.Printf("source: %s unable to save: %v", article.String(), saveArticleErr)
+				continue
+			}
+
+			if !isNew {
+				continue
+			}
+
+			saved++
+
+			log.Printf("source: %s %s saved", source.String(), article.String())
+		}
+
+		return saved
+	}
+
 	crawl := func() {
-		runnerErr := runner.ForEachSource(context.Background(), func(source *database.Source) error {
+		runnerErr := runner.ForEachSource(context.Background(), func(run time.Time, source *database.Source) (err error) {
+			// Без браузера страницы со скриптами не загрузятся, и источник
+			// зря израсходует попытки. Обход переносится на следующий тик.
+			if source.Config.NeedsBrowser() {
+				if err := chrome.Health(); err != nil {
+					return fmt.Errorf("%w: %v", task.ErrSkipped, err)
+				}
+			}
+
 			log.Printf("source: %s requesting", source.String())
 
-			ctx, cancel := context.WithTimeout(browserCtx, 10*time.Second)
-			defer cancel()
+			summary := newCrawlSummary()
+			defer func() {
+				// Браузер пропал посреди обхода: попытка не расходуется,
+				// источник будет обработан в следующий раз.
+				if errors.Is(err, browser.ErrUnavailable) {
+					err = fmt.Errorf("%w: %v", task.ErrSkipped, err)
+					return
+				}
 
-			body, err := browser.FetchHTML(ctx, source.URL)
+				saveCrawl(service, source, run, summary, err)
+				checkBreakage(service, alerter, breakageRules, source)
+			}()
+
+			filter, err := parsing.NewFilter(source.Config.Include, source.Config.Exclude)
 			if err != nil {
-				log.Printf("source: %s request failed", source.String())
-				return err
+				return fmt.Errorf("source: %s invalid filters: %v", source.String(), err)
+			}
+
+			// Ленты не требуют рендеринга и загружаются без браузера.
+			if source.Config.IsFeed() {
+				ctx, cancel := context.WithTimeout(appCtx, 10*time.Second)
+				defer cancel()
+
+				parserSource := encodeParserSource(source)
+				if len(source.Config.FeedURL) > 0 {
+					parserSource.URL = source.Config.FeedURL
+				}
+
+				body, err := httpFetcher.Fetch(ctx, parserSource.URL)
+				if err != nil {
+					log.Printf("source: %s request failed", source.String())
+					return err
+				}
+
+				log.Printf("source: %s request succeded", source.String())
+
+				result, err := parser.ParseFeed(parserSource, body)
+				if err != nil {
+					// Лента загрузилась, но не разбирается: вместо неё,
+					// например, отдаётся HTML. Это поломка, а не сбой сети.
+					summary.add(parsing.ParseResult{}, 0)
+					return err
+				}
+
+				summary.add(result, saveArticles(source, filter, result.Articles))
+				summary.log(source, 1, result)
+
+				return nil
+			}
+
+			// Карта сайта загружается без браузера. Заголовки статей, которых
+			// нет в карте, берутся со страниц новых статей.
+			if source.Config.Type == config.TypeSitemap {
+				ctx, cancel := context.WithTimeout(appCtx, time.Minute)
+				defer cancel()
+
+				articles, err := parser.WalkSitemap(ctx, encodeParserSource(source), httpFetcher.Fetch)
+				if err != nil {
+					return err
+				}
+
+				log.Printf("source: %s %d sitemap entries found", source.String(), len(articles))
+
+				parserSource := encodeParserSource(source)
+				titled := make([]parsing.Article, 0, len(articles))
+				enriched, deferred := 0, 0
+
+				for _, article := range articles {
+					// Отброшенная по адресу статья сохраняется фильтром без загрузки страницы.
+					if len(article.Title) == 0 && filter.MatchURL(article.DetailURL) {
+						if knownArticle(appCtx, service, source, article) {
+							continue
+						}
+
+						// Остальные новые статьи дополнятся при следующих обходах.
+						if enriched == maxSitemapEnrichments {
+							deferred++
+							continue
+						}
+						enriched++
+
+						article = enrichArticle(appCtx, service, httpFetcher, parser, source, article)
+						article.Title = parserSource.RewriteTitle(article.Title)
+
+						// Страница статьи недоступна.
+						if len(article.Title) == 0 {
+							continue
+						}
+					}
+
+					titled = append(titled, article)
+				}
+
+				if deferred > 0 {
+					log.Printf("source: %s enrichment limit reached, %d articles deferred", source.String(), deferred)
+				}
+
+				result := parsing.ParseResult{Matched: len(articles), Articles: titled}
+				summary.add(result, saveArticles(source, filter, titled))
+				summary.log(source, 1, result)
+
+				return nil
+			}
+
+			// Изменение отслеживаемой страницы сохраняется как статья
+			// с описанием различий. Первый снимок только запоминается.
+			if source.Config.Type == config.TypeWatch {
+				pages, timeout, err := pageFetcher(source)
+				if err != nil {
+					return err
+				}
+
+				body, err := fetchPage(appCtx, pages, timeout, source.URL)
+				if err != nil {
+					log.Printf("source: %s request failed", source.String())
+					return err
+				}
+
+				log.Printf("source: %s request succeded", source.String())
+
+				parserSource := encodeParserSource(source)
+
+				snapshot, err := parser.Snapshot(parserSource, body)
+				if err != nil {
+					// Страница загрузилась, но область не нашлась - признак
+					// поломки источника, а не сбоя сети.
+					summary.add(parsing.ParseResult{}, 0)
+					return err
+				}
+
+				previous, err := service.LastSnapshot(appCtx, source.ID)
+				first := errors.Is(err, sql.ErrNoRows)
+				if err != nil && !first {
+					return fmt.Errorf("source: %s unable to load snapshot: %v", source.String(), err)
+				}
+
+				if !first && previous.Hash == snapshot.Hash {
+					summary.add(parsing.ParseResult{Matched: 1}, 0)
+					log.Printf("source: %s page not changed", source.String())
+					return service.TouchSnapshot(appCtx, previous.ID)
+				}
+
+				if err := service.SaveSnapshot(appCtx, source.ID, snapshot.Hash, snapshot.Text); err != nil {
+					return fmt.Errorf("source: %s unable to save snapshot: %v", source.String(), err)
+				}
+
+				if first {
+					summary.add(parsing.ParseResult{Matched: 1}, 0)
+					log.Printf("source: %s first snapshot saved", source.String())
+					return nil
+				}
+
+				article := parser.ChangeArticle(parserSource, parsing.Snapshot{Text: previous.Text, Hash: previous.Hash}, snapshot)
+				log.Printf("source: %s page changed", source.String())
+
+				summary.add(parsing.ParseResult{Matched: 1, Articles: []parsing.Article{article}}, saveArticles(source, filter, []parsing.Article{article}))
+
+				return nil
+			}
+
+			// JSON API также загружается без браузера, ссылки на статьи
+			// разрешаются относительно адреса источника.
+			if source.Config.Type == config.TypeJSON {
+				ctx, cancel := context.WithTimeout(appCtx, 10*time.Second)
+				defer cancel()
+
+				apiURL := source.URL
+				if len(source.Config.APIURL) > 0 {
+					apiURL = source.Config.APIURL
+				}
+
+				body, err := httpFetcher.Fetch(ctx, apiURL)
+				if err != nil {
+					log.Printf("source: %s request failed", source.String())
+					return err
+				}
+
+				log.Printf("source: %s request succeded", source.String())
+
+				result, err := parser.ParseJSON(encodeParserSource(source), body)
+				if err != nil {
+					summary.add(parsing.ParseResult{}, 0)
+					return err
+				}
+
+				summary.add(result, saveArticles(source, filter, result.Articles))
+				summary.log(source, 1, result)
+
+				return nil
+			}
+
+			// Статьи SPA извлекаются из перехваченных ответов API, а не из разметки.
+			if len(source.Config.Capture) > 0 {
+				opts, err := browser.NewOptions(source.Config)
+				if err != nil {
+					return fmt.Errorf("source: %s invalid browser options: %v", source.String(), err)
+				}
+
+				page, err := capturePage(appCtx, browserPool, source.URL, source.Config.Capture, opts)
+				if err != nil {
+					log.Printf("source: %s request failed", source.String())
+					return err
+				}
+
+				// Страница загрузилась, но API больше не отвечает по шаблону.
+				if len(page.Responses) == 0 {
+					summary.add(parsing.ParseResult{}, 0)
+					return fmt.Errorf("source: %s no responses matching %s captured", source.String(), source.Config.Capture)
+				}
+
+				log.Printf("source: %s request succeded, %d responses captured", source.String(), len(page.Responses))
+
+				parserSource := encodeParserSource(source)
+
+				for i, response := range page.Responses {
+					result, err := parser.ParseJSON(parserSource, response.Body)
+					if err != nil {
+						log.Printf("source: %s response %s: %v", source.String(), response.URL, err)
+						summary.add(parsing.ParseResult{}, 0)
+						continue
+					}
+
+					summary.add(result, saveArticles(source, filter, result.Articles))
+					summary.log(source, i+1, result)
+				}
+
+				return nil
 			}
 
-			log.Printf("source: %s request succeded", source.String())
+			maxPages := 1
+			if source.Config.MaxPages > 1 {
+				maxPages = source.Config.MaxPages
+			}
 
-			articles, err := parser.Parse(encodeParserSource(source), body)
+			pages, timeout, err := pageFetcher(source)
 			if err != nil {
 				return err
 			}
 
-			for _, article := range articles {
-				saveArticleErr := service.SaveArticle(context.Background(), database.SaveArticleParams{
-					SourceID: source.ID,
-					Title:    article.Title,
-					Url:      article.DetailURL,
-					Added:    unix.TimeNow(),
-				})
-				if saveArticleErr != nil {
-					log.Printf("source: %s unable to save: %v", article.String(), saveArticleErr)
-					continue
+			pageURL := source.URL
+
+			for page := 1; page <= maxPages && len(pageURL) > 0; page++ {
+				parserSource := encodeParserSource(source)
+				parserSource.URL = pageURL
+
+				body, err := fetchPage(appCtx, pages, timeout, pageURL)
+				if err != nil {
+					log.Printf("source: %s page %d request failed", source.String(), page)
+					// Сбой на последующих страницах не отменяет уже сохранённое.
+					if page > 1 {
+						return nil
+					}
+					return err
+				}
+
+				log.Printf("source: %s page %d request succeded", source.String(), page)
+
+				result, err := parser.Parse(parserSource, body)
+				if err != nil {
+					return err
+				}
+
+				saved := saveArticles(source, filter, result.Articles)
+				summary.add(result, saved)
+				summary.log(source, page, result)
+
+				// Ни одной карточки на первой странице - вероятнее всего,
+				// селекторы устарели, в отличие от страницы без новых статей.
+				if result.Matched == 0 {
+					if page == 1 {
+						return fmt.Errorf("source: %s no cards matched %s", source.String(), parserSource.ArticleSelector)
+					}
+
+					log.Printf("source: %s page %d no cards matched, stopping", source.String(), page)
+					break
+				}
+
+				if !parserSource.HasPagination() {
+					break
 				}
 
-				log.Printf("source: %s %s saved", source.String(), article.String())
+				// Страница целиком из известных статей - дальше только старые.
+				if saved == 0 {
+					log.Printf("source: %s page %d has no new articles, stopping", source.String(), page)
+					break
+				}
+
+				pageURL, err = parser.NextPageURL(parserSource, body, page)
+				if err != nil {
+					log.Printf("source: %s %v", source.String(), err)
+					break
+				}
 			}
 
 			return nil
@@ -160,6 +555,12 @@ func main() {
 		}
 		log.Printf("sender: enabling sender with interval %s\n", sendInterval.String())
 
+		// Статьи старше sendMaxAge по дате публикации не отправляются.
+		sendMaxAge, err := time.ParseDuration(confSendMaxAge)
+		if err != nil {
+			log.Fatalf("crawler: unable to parse send max age %s: %v", confSendMaxAge, err)
+		}
+
 		sendTicker := time.NewTicker(sendInterval)
 
 		go func() {
@@ -177,10 +578,25 @@ func main() {
 						continue
 					}
 
-					sendErr := service.SelectUnsent(context.Background(), func(article database.Article) error {
+					var publishedAfter time.Time
+					if sendMaxAge > 0 {
+						publishedAfter = time.Now().Add(-sendMaxAge)
+					}
+
+					sendErr := service.SelectUnsent(context.Background(), publishedAfter, func(article database.Article) error {
 						log.Printf("sender: sending article %s", article.String())
 
-						if err := publisher.PublishPost(article.Source, article.Title, article.URL, article.Channels, article.Tags); err != nil {
+						post := telegram.Post{
+							Source:       article.Source,
+							Title:        article.Title,
+							URL:          article.URL,
+							CanonicalURL: article.CanonicalURL,
+							Description:  article.Summary,
+							Channels:     article.Channels,
+							Tags:         article.Tags,
+						}
+
+						if err := publisher.PublishPost(post); err != nil {
 							if after, ok := telegram.CanRetry(err); ok {
 								sendAfter = time.Now().Add(time.Duration(after) * time.Second)
 								log.Printf("sender: rate limit exceeded, retrying after %d seconds", after)
@@ -214,12 +630,214 @@ func main() {
 	<-appCtx.Done()
 }
 
-func encodeParserSource(source *database.Source) parsing.Source {
-	return parsing.Source{
-		URL:             source.URL,
-		Name:            source.Name,
-		ArticleSelector: source.Config.ArticleSelector,
-		TitleSelector:   source.Config.TitleSelector,
-		DetailSelector:  source.Config.DetailSelector,
+// enrichArticle дополняет новую статью метаданными её страницы.
+// Уже известные статьи не загружаются, ошибки загрузки не мешают
+// сохранению статьи с данными из списка.
+func enrichArticle(
+	ctx context.Context,
+	service *database.Service,
+	httpFetcher fetcher.Fetcher,
+	parser *parsing.Parser,
+	source *database.Source,
+	article parsing.Article,
+) parsing.Article {
+	exists, err := service.ArticleExists(ctx, article.DetailURL)
+	if err != nil {
+		log.Printf("source: %s unable to check %s: %v", source.String(), article.String(), err)
+		return article
+	}
+
+	if exists {
+		return article
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	body, err := httpFetcher.Fetch(ctx, article.DetailURL)
+	if err != nil {
+		log.Printf("source: %s unable to enrich %s: %v", source.String(), article.String(), err)
+		return article
+	}
+
+	meta, err := parser.ParseMeta(article.DetailURL, body)
+	if err != nil {
+		log.Printf("source: %s unable to enrich %s: %v", source.String(), article.String(), err)
+		return article
+	}
+
+	log.Printf("source: %s %s enriched", source.String(), article.String())
+
+	return article.Enrich(meta)
+}
+
+// knownArticle сообщает, что статья уже сохранена или отброшена фильтрами,
+// и загружать её страницу не нужно.
+func knownArticle(ctx context.Context, service *database.Service, source *database.Source, article parsing.Article) bool {
+	exists, err := service.ArticleExists(ctx, article.DetailURL)
+	if err == nil && !exists {
+		exists, err = service.FilteredArticleExists(ctx, article.DetailURL)
+	}
+
+	if err != nil {
+		log.Printf("source: %s unable to check %s: %v", source.String(), article.String(), err)
+		return false
+	}
+
+	return exists
+}
+
+// maxSitemapEnrichments наибольшее количество страниц статей, загружаемых
+// за один обход карты сайта ради заголовков.
+const maxSitemapEnrichments = 20
+
+// sourceRetries количество попыток обработать источник за один запуск раннера.
+const sourceRetries = 3
+
+// pageTimeout время загрузки страницы без учёта ожидания условий готовности
+// и действий на странице.
+const pageTimeout = 10 * time.Second
+
+// captureTimeout время загрузки страницы с перехватом ответов XHR/fetch
+// без учёта ожидания условий готовности и действий на странице: ответы
+// API могут прийти уже после события load.
+const captureTimeout = 20 * time.Second
+
+func fetchPage(ctx context.Context, f fetcher.Fetcher, timeout time.Duration, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return f.Fetch(ctx, url)
+}
+
+func capturePage(ctx context.Context, pool *browser.Pool, url, capture string, opts browser.Options) (browser.Page, error) {
+	ctx, cancel := context.WithTimeout(ctx, captureTimeout+opts.Duration())
+	defer cancel()
+
+	var page browser.Page
+
+	err := pool.Do(ctx, func(tabCtx context.Context) error {
+		var err error
+		page, err = browser.FetchPage(tabCtx, url, capture, opts)
+		return err
+	})
+
+	return page, err
+}
+
+// crawlSummary итог обхода источника по всем загруженным страницам.
+type crawlSummary struct {
+	started time.Time
+	pages   int
+	saved   int
+	// total объединённый результат разбора всех страниц.
+	total parsing.ParseResult
+}
+
+func newCrawlSummary() *crawlSummary {
+	return &crawlSummary{started: time.Now()}
+}
+
+func (c *crawlSummary) add(result parsing.ParseResult, saved int) {
+	c.pages++
+	c.saved += saved
+	c.total.Matched += result.Matched
+	c.total.Articles = append(c.total.Articles, result.Articles...)
+	c.total.Rejected = append(c.total.Rejected, result.Rejected...)
+}
+
+func (c *crawlSummary) log(source *database.Source, page int, result parsing.ParseResult) {
+	if len(result.Rejected) == 0 {
+		log.Printf("source: %s page %d: %d cards matched, %d accepted", source.String(), page, result.Matched, len(result.Articles))
+		return
+	}
+
+	log.Printf(
+		"source: %s page %d: %d cards matched, %d accepted, %d rejected (%s)",
+		source.String(), page, result.Matched, len(result.Articles), len(result.Rejected), result.RejectionSummary(),
+	)
+}
+
+// saveCrawl сохраняет итог обхода. Ошибка сохранения только логируется.
+func saveCrawl(service *database.Service, source *database.Source, run time.Time, summary *crawlSummary, crawlErr error) {
+	var errText string
+	if crawlErr != nil {
+		errText = crawlErr.Error()
+	}
+
+	err := service.SaveCrawl(context.Background(), database.SaveCrawlParams{
+		SourceID: source.ID,
+		Started:  unix.FromTime(summary.started),
+		Finished: unix.TimeNow(),
+		Pages:    int64(summary.pages),
+		Matched:  int64(summary.total.Matched),
+		Accepted: int64(len(summary.total.Articles)),
+		Rejected: int64(len(summary.total.Rejected)),
+		Saved:    int64(summary.saved),
+		Reasons:  summary.total.RejectionSummary(),
+		Error:    errText,
+		Run:      unix.FromTime(run),
+	})
+	if err != nil {
+		log.Printf("source: %s unable to save crawl: %v", source.String(), err)
+	}
+}
+
+// checkBreakage помечает источник сломанным по истории обходов
+// и уведомляет администратора. Пометка снимается после удачного обхода.
+func checkBreakage(service *database.Service, alerter *telegram.Publisher, rules task.BreakageRules, source *database.Source) {
+	ctx := context.Background()
+
+	crawls, err := service.RecentCrawls(ctx, source.ID, rules.HistoryLimit())
+	if err != nil {
+		log.Printf("source: %s unable to load crawls: %v", source.String(), err)
+		return
+	}
+
+	breakage, broken := task.DetectBreakage(crawls, rules)
+
+	if !broken {
+		if source.Broken && len(crawls) > 0 && crawls[0].Matched > 0 {
+			if err := service.SetSourceBroken(ctx, source.ID, false); err != nil {
+				log.Printf("source: %s unable to reset broken: %v", source.String(), err)
+				return
+			}
+
+			source.Broken = false
+			log.Printf("source: %s recovered", source.String())
+		}
+
+		return
+	}
+
+	// Об уже сломанном источнике администратор знает.
+	if source.Broken {
+		return
+	}
+
+	if err := service.SetSourceBroken(ctx, source.ID, true); err != nil {
+		log.Printf("source: %s unable to mark broken: %v", source.String(), err)
+		return
 	}
+
+	source.Broken = true
+	log.Printf("source: %s marked broken: %s", source.String(), breakage.Reason)
+
+	if alerter == nil {
+		return
+	}
+
+	alertErr := alerter.PublishAlert(confAdminChat, telegram.Alert{
+		Source:   source.Name,
+		URL:      source.URL,
+		Reason:   breakage.Reason,
+		LastGood: breakage.LastGood,
+	})
+	if alertErr != nil {
+		log.Printf("source: %s unable to send alert: %v", source.String(), alertErr)
+	}
+}
+
+func encodeParserSource(source *database.Source) parsing.Source {
+	return parsing.NewSource(source.Name, source.URL, source.Config)
 }
@@ -0,0 +1,187 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+const (
+	// actionTimeout предельное время нажатия и выполнения скрипта.
+	actionTimeout = 5 * time.Second
+	// scrollPause пауза после прокрутки, за которую подгружаются карточки.
+	scrollPause = 500 * time.Millisecond
+	// maxScroll ограничивает прокрутку бесконечных лент.
+	maxScroll = 50
+)
+
+// keyNames символы клавиш по названиям: "Escape", "End", "PageDown".
+var keyNames = func() map[string]string {
+	names := make(map[string]string, len(kb.Keys))
+	for r, key := range kb.Keys {
+		if utf8.RuneCountInString(key.Key) > 1 {
+			names[key.Key] = string(r)
+		}
+	}
+	return names
+}()
+
+// Action действие на странице после загрузки. Задано ровно одно
+// из действий: Scroll, Click, Wait, Press или Evaluate.
+type Action struct {
+	Scroll   int
+	Click    string
+	Wait     *Wait
+	Press    string
+	Evaluate string
+	// Optional ошибка действия только записывается в лог.
+	Optional bool
+}
+
+// NewActions разбирает действия на странице из конфигурации источника.
+func NewActions(rules []config.ActionRule) ([]Action, error) {
+	actions := make([]Action, 0, len(rules))
+
+	for i, rule := range rules {
+		a := Action{
+			Scroll:   rule.Scroll,
+			Click:    strings.TrimSpace(rule.Click),
+			Press:    rule.Press,
+			Evaluate: strings.TrimSpace(rule.Evaluate),
+			Optional: rule.Optional,
+		}
+
+		if a.Scroll < 0 || a.Scroll > maxScroll {
+			return nil, fmt.Errorf("action %d: scroll must be between 1 and %d", i, maxScroll)
+		}
+
+		if rule.Wait != nil {
+			waits, err := NewWaits([]config.WaitRule{*rule.Wait})
+			if err != nil {
+				return nil, fmt.Errorf("action %d: %v", i, err)
+			}
+			// Простой сети отслеживается только при загрузке страницы.
+			if waits[0].NetworkIdle {
+				return nil, fmt.Errorf("action %d: network_idle wait is only supported after navigation", i)
+			}
+			a.Wait = &waits[0]
+		}
+
+		if len(a.Press) > 0 && utf8.RuneCountInString(a.Press) > 1 {
+			key, ok := keyNames[a.Press]
+			if !ok {
+				return nil, fmt.Errorf("action %d: unknown key %q", i, a.Press)
+			}
+			a.Press = key
+		}
+
+		conditions := 0
+		for _, set := range []bool{a.Scroll > 0, len(a.Click) > 0, a.Wait != nil, len(a.Press) > 0, len(a.Evaluate) > 0} {
+			if set {
+				conditions++
+			}
+		}
+
+		if conditions != 1 {
+			return nil, fmt.Errorf("action %d: exactly one of scroll, click, wait, press or evaluate is required", i)
+		}
+
+		actions = append(actions, a)
+	}
+
+	return actions, nil
+}
+
+// ActionsDuration наибольшее суммарное время выполнения действий.
+func ActionsDuration(actions []Action) time.Duration {
+	var total time.Duration
+
+	for _, a := range actions {
+		switch {
+		case a.Scroll > 0:
+			total += time.Duration(a.Scroll) * scrollPause
+		case a.Wait != nil:
+			total += WaitsDuration([]Wait{*a.Wait})
+		case len(a.Click) > 0, len(a.Evaluate) > 0:
+			total += actionTimeout
+		}
+	}
+
+	return total
+}
+
+func (a Action) String() string {
+	switch {
+	case a.Scroll > 0:
+		return fmt.Sprintf("scroll %d", a.Scroll)
+	case len(a.Click) > 0:
+		return fmt.Sprintf("click %q", a.Click)
+	case a.Wait != nil:
+		return fmt.Sprintf("wait %s", a.Wait)
+	case len(a.Press) > 0:
+		if key, ok := kb.Keys[[]rune(a.Press)[0]]; ok {
+			return fmt.Sprintf("press %s", key.Key)
+		}
+		return fmt.Sprintf("press %q", a.Press)
+	}
+	return fmt.Sprintf("evaluate %q", a.Evaluate)
+}
+
+// action выполняет действие. Ошибка необязательного действия
+// записывается в лог и не прерывает загрузку.
+func (a Action) action(url string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		err := a.do(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		if a.Optional {
+			log.Printf("browser: optional action %s failed on %s: %v\n", a, url, err)
+			return nil
+		}
+
+		return fmt.Errorf("action %s: %w", a, err)
+	})
+}
+
+func (a Action) do(ctx context.Context) error {
+	switch {
+	case a.Scroll > 0:
+		for i := 0; i < a.Scroll; i++ {
+			err := chromedp.Evaluate(`window.scrollTo(0, document.documentElement.scrollHeight)`, nil).Do(ctx)
+			if err != nil {
+				return err
+			}
+
+			if err := chromedp.Sleep(scrollPause).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	case a.Wait != nil:
+		return a.Wait.action(nil).Do(ctx)
+	case len(a.Press) > 0:
+		return chromedp.KeyEvent(a.Press).Do(ctx)
+	}
+
+	actionCtx, cancel := context.WithTimeout(ctx, actionTimeout)
+	defer cancel()
+
+	if len(a.Click) > 0 {
+		return chromedp.Click(a.Click, chromedp.ByQuery, chromedp.NodeVisible).Do(actionCtx)
+	}
+
+	return chromedp.Evaluate(a.Evaluate, nil, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	}).Do(actionCtx)
+}
@@ -0,0 +1,129 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp/kb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestNewActions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		rules   []config.ActionRule
+		want    []Action
+		wantErr bool
+	}{
+		{
+			name: "load more",
+			rules: []config.ActionRule{
+				{Click: "#cookie-accept", Optional: true},
+				{Scroll: 3},
+				{Click: " button.load-more "},
+				{Wait: &config.WaitRule{Selector: "article:nth-of-type(20)", Timeout: "3s"}},
+			},
+			want: []Action{
+				{Click: "#cookie-accept", Optional: true},
+				{Scroll: 3},
+				{Click: "button.load-more"},
+				{Wait: &Wait{Selector: "article:nth-of-type(20)", Timeout: 3 * time.Second}},
+			},
+		},
+		{
+			name:  "named key",
+			rules: []config.ActionRule{{Press: "Escape"}},
+			want:  []Action{{Press: kb.Escape}},
+		},
+		{
+			name:  "character key",
+			rules: []config.ActionRule{{Press: "j"}, {Evaluate: "window.loadAll()"}},
+			want:  []Action{{Press: "j"}, {Evaluate: "window.loadAll()"}},
+		},
+		{
+			name:    "unknown key",
+			rules:   []config.ActionRule{{Press: "Launch"}},
+			wantErr: true,
+		},
+		{
+			name:    "no action",
+			rules:   []config.ActionRule{{Optional: true}},
+			wantErr: true,
+		},
+		{
+			name:    "two actions",
+			rules:   []config.ActionRule{{Scroll: 2, Click: "button"}},
+			wantErr: true,
+		},
+		{
+			name:    "too many scrolls",
+			rules:   []config.ActionRule{{Scroll: 1000}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid wait",
+			rules:   []config.ActionRule{{Wait: &config.WaitRule{Delay: "soon"}}},
+			wantErr: true,
+		},
+		{
+			name:    "network idle wait",
+			rules:   []config.ActionRule{{Wait: &config.WaitRule{NetworkIdle: true}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got, err := NewActions(tc.rules)
+
+			// assert
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestOptionsDuration(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{
+		Waits: []Wait{{Selector: "article", Timeout: 5 * time.Second}},
+		Actions: []Action{
+			{Scroll: 4},
+			{Click: "button.load-more"},
+			{Wait: &Wait{Delay: time.Second}},
+			{Press: kb.End},
+		},
+	}
+
+	// act
+	got := opts.Duration()
+
+	// assert
+	require.Equal(t, 5*time.Second+2*time.Second+actionTimeout+time.Second, got)
+}
+
+func TestActionString(t *testing.T) {
+	t.Parallel()
+
+	// act & assert
+	require.Equal(t, "scroll 2", Action{Scroll: 2}.String())
+	require.Equal(t, `click "button"`, Action{Click: "button"}.String())
+	require.Equal(t, "wait delay 1s", Action{Wait: &Wait{Delay: time.Second}}.String())
+	require.Equal(t, "press Escape", Action{Press: kb.Escape}.String())
+	require.Equal(t, `evaluate "window.loadAll()"`, Action{Evaluate: "window.loadAll()"}.String())
+}
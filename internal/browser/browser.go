@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"time"
 
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/fetch"
@@ -17,13 +16,18 @@ func Run(ctx context.Context) error {
 	return chromedp.Run(ctx)
 }
 
-func FetchHTML(ctx context.Context, url string) (string, error) {
+// FetchHTML загружает страницу и возвращает её HTML после выполнения
+// условий готовности и действий на странице из opts.
+func FetchHTML(ctx context.Context, url string, opts Options) (string, error) {
+	tasks, cancel := navigate(ctx, url, opts.Waits)
+	defer cancel()
+
 	var body string
 	return body, chromedp.Run(
 		ctx,
 		fetch.Enable(),
-		chromedp.Navigate(url),
-		chromedp.Sleep(time.Second),
+		tasks,
+		opts.actions(url),
 		chromedp.InnerHTML(`html`, &body),
 	)
 }
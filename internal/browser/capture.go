@@ -0,0 +1,154 @@
+package browser
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// captureWait время ожидания первого подходящего ответа после загрузки
+// страницы: данные SPA часто запрашиваются уже после события load.
+const captureWait = 5 * time.Second
+
+// Response тело ответа на запрос XHR/fetch, перехваченного при загрузке страницы.
+type Response struct {
+	URL  string
+	Body string
+}
+
+// Page отрендеренная страница и перехваченные ответы.
+type Page struct {
+	HTML      string
+	Responses []Response
+}
+
+// FetchPage загружает страницу, как FetchHTML, и дополнительно сохраняет
+// тела ответов XHR/fetch, адрес которых соответствует шаблону capture.
+// В шаблоне "*" обозначает любую последовательность символов:
+// "https://example.com/api/posts*".
+// Действия на странице выполняются после ожидания первого ответа,
+// ответы на запросы, вызванные действиями, тоже сохраняются.
+func FetchPage(ctx context.Context, url, capture string, opts Options) (Page, error) {
+	var page Page
+
+	match := captureMatcher(capture)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		closed   bool
+		matched  = make(map[network.RequestID]string)
+		captured = make(chan struct{})
+		once     sync.Once
+	)
+
+	listenCtx, cancelListen := context.WithCancel(ctx)
+	defer cancelListen()
+
+	chromedp.ListenTarget(listenCtx, func(event interface{}) {
+		switch ev := event.(type) {
+		case *network.EventResponseReceived:
+			if !capturable(ev, match) {
+				return
+			}
+
+			mu.Lock()
+			matched[ev.RequestID] = ev.Response.URL
+			mu.Unlock()
+		case *network.EventLoadingFinished:
+			mu.Lock()
+			responseURL, ok := matched[ev.RequestID]
+			delete(matched, ev.RequestID)
+			start := ok && !closed
+			if start {
+				wg.Add(1)
+			}
+			mu.Unlock()
+
+			if !start {
+				return
+			}
+
+			go func() {
+				defer wg.Done()
+
+				c := chromedp.FromContext(ctx)
+				ctx := cdp.WithExecutor(ctx, c.Target)
+
+				body, err := network.GetResponseBody(ev.RequestID).Do(ctx)
+				if err != nil {
+					log.Printf("browser: unable to capture response %s: %v\n", responseURL, err)
+					return
+				}
+
+				mu.Lock()
+				page.Responses = append(page.Responses, Response{URL: responseURL, Body: string(body)})
+				mu.Unlock()
+
+				once.Do(func() { close(captured) })
+			}()
+		}
+	})
+
+	tasks, cancelNavigate := navigate(ctx, url, opts.Waits)
+	defer cancelNavigate()
+
+	err := chromedp.Run(
+		ctx,
+		network.Enable(),
+		fetch.Enable(),
+		tasks,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			waitCtx, cancel := context.WithTimeout(ctx, captureWait)
+			defer cancel()
+
+			select {
+			case <-captured:
+			case <-waitCtx.Done():
+				log.Printf("browser: no responses matching %s captured on %s\n", capture, url)
+			}
+			return nil
+		}),
+		opts.actions(url),
+		chromedp.InnerHTML(`html`, &page.HTML),
+	)
+
+	// Ответы, запрошенные после рендеринга, уже не нужны.
+	cancelListen()
+	mu.Lock()
+	closed = true
+	mu.Unlock()
+	wg.Wait()
+
+	return page, err
+}
+
+// capturable сообщает, что ответ получен на запрос XHR/fetch и его адрес
+// подходит под шаблон. Документы, скрипты и картинки не сохраняются.
+func capturable(ev *network.EventResponseReceived, match func(string) bool) bool {
+	if ev.Type != network.ResourceTypeXHR && ev.Type != network.ResourceTypeFetch {
+		return false
+	}
+
+	return ev.Response != nil && match(ev.Response.URL)
+}
+
+// captureMatcher компилирует шаблон адреса со звёздочками.
+func captureMatcher(pattern string) func(string) bool {
+	parts := strings.Split(strings.TrimSpace(pattern), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	re := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+
+	return re.MatchString
+}
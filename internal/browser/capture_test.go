@@ -0,0 +1,149 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureMatcher(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		pattern string
+		url     string
+		want    bool
+	}{
+		{
+			name:    "exact url",
+			pattern: "https://example.com/api/posts",
+			url:     "https://example.com/api/posts",
+			want:    true,
+		},
+		{
+			name:    "exact url with query",
+			pattern: "https://example.com/api/posts",
+			url:     "https://example.com/api/posts?page=2",
+		},
+		{
+			name:    "trailing star",
+			pattern: "https://example.com/api/posts*",
+			url:     "https://example.com/api/posts?page=2",
+			want:    true,
+		},
+		{
+			name:    "star in the middle",
+			pattern: "https://example.com/api/*/posts",
+			url:     "https://example.com/api/v2/posts",
+			want:    true,
+		},
+		{
+			name:    "star matches empty string",
+			pattern: "https://example.com/api/posts*",
+			url:     "https://example.com/api/posts",
+			want:    true,
+		},
+		{
+			name:    "regexp characters are literal",
+			pattern: "https://example.com/api/posts?page=*",
+			url:     "https://example.com/api/postspage=1",
+		},
+		{
+			name:    "prefix does not match",
+			pattern: "https://example.com/api/posts*",
+			url:     "https://cdn.example.com/api/posts",
+		},
+		{
+			name:    "surrounding spaces are ignored",
+			pattern: "  https://example.com/api/*  ",
+			url:     "https://example.com/api/posts",
+			want:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			match := captureMatcher(tc.pattern)
+
+			// act
+			ok := match(tc.url)
+
+			// assert
+			require.Equal(t, tc.want, ok)
+		})
+	}
+}
+
+func TestCapturable(t *testing.T) {
+	t.Parallel()
+
+	match := captureMatcher("https://example.com/api/posts*")
+
+	cases := []struct {
+		name  string
+		event *network.EventResponseReceived
+		want  bool
+	}{
+		{
+			name: "xhr",
+			event: &network.EventResponseReceived{
+				Type:     network.ResourceTypeXHR,
+				Response: &network.Response{URL: "https://example.com/api/posts?page=1"},
+			},
+			want: true,
+		},
+		{
+			name: "fetch",
+			event: &network.EventResponseReceived{
+				Type:     network.ResourceTypeFetch,
+				Response: &network.Response{URL: "https://example.com/api/posts"},
+			},
+			want: true,
+		},
+		{
+			name: "other api url",
+			event: &network.EventResponseReceived{
+				Type:     network.ResourceTypeFetch,
+				Response: &network.Response{URL: "https://example.com/api/users"},
+			},
+		},
+		{
+			name: "document",
+			event: &network.EventResponseReceived{
+				Type:     network.ResourceTypeDocument,
+				Response: &network.Response{URL: "https://example.com/api/posts"},
+			},
+		},
+		{
+			name: "script",
+			event: &network.EventResponseReceived{
+				Type:     network.ResourceTypeScript,
+				Response: &network.Response{URL: "https://example.com/api/posts.js"},
+			},
+		},
+		{
+			name:  "no response",
+			event: &network.EventResponseReceived{Type: network.ResourceTypeXHR},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			ok := capturable(tc.event, match)
+
+			// assert
+			require.Equal(t, tc.want, ok)
+		})
+	}
+}
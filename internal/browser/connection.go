@@ -0,0 +1,209 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// reconnectMinDelay пауза перед повторной попыткой подключения.
+	reconnectMinDelay = time.Second
+	// reconnectMaxDelay наибольшая пауза между попытками.
+	reconnectMaxDelay = time.Minute
+)
+
+// ErrUnavailable подключение к браузеру потеряно и ещё не восстановлено.
+var ErrUnavailable = errors.New("browser unavailable")
+
+// errConnectionLost причина недоступности до первой попытки переподключения.
+var errConnectionLost = errors.New("connection lost")
+
+var errClosed = errors.New("closed")
+
+// Browser подключение к Chrome. Потерянное подключение, например после
+// перезапуска контейнера headless-shell, восстанавливается в фоне
+// с нарастающей паузой между попытками.
+type Browser struct {
+	connect func() (context.Context, context.CancelFunc)
+
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	// err причина недоступности, nil - браузер доступен.
+	err       error
+	closed    bool
+	stopWatch context.CancelFunc
+}
+
+// NewBrowser создаёт подключение, которое открывается функцией connect.
+func NewBrowser(connect func() (context.Context, context.CancelFunc)) *Browser {
+	return &Browser{connect: connect, err: errConnectionLost}
+}
+
+// NewLocalBrowser запускает локальный Chrome, см. NewLocalContext.
+func NewLocalBrowser() *Browser {
+	return NewBrowser(NewLocalContext)
+}
+
+// NewRemoteBrowser подключается к Chrome по адресу url, см. NewRemoteContext.
+func NewRemoteBrowser(url string) *Browser {
+	return NewBrowser(func() (context.Context, context.CancelFunc) {
+		return NewRemoteContext(url)
+	})
+}
+
+// Start подключается к браузеру и до отмены ctx восстанавливает
+// подключение после потери.
+func (b *Browser) Start(ctx context.Context) error {
+	if err := b.reconnect(); err != nil {
+		return err
+	}
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+
+	b.mu.Lock()
+	b.stopWatch = stopWatch
+	b.mu.Unlock()
+
+	go b.watch(watchCtx)
+
+	return nil
+}
+
+// Health возвращает nil, если браузер доступен, иначе - ошибку
+// ErrUnavailable с причиной.
+func (b *Browser) Health() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, b.err)
+	}
+
+	return nil
+}
+
+// Close закрывает подключение без попыток его восстановить.
+func (b *Browser) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopWatch != nil {
+		b.stopWatch()
+	}
+
+	if b.cancel != nil {
+		b.cancel()
+	}
+
+	b.closed = true
+	b.err = errClosed
+}
+
+// context возвращает контекст текущего подключения к браузеру.
+func (b *Browser) context() (context.Context, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, b.err)
+	}
+
+	return b.ctx, nil
+}
+
+func (b *Browser) watch(ctx context.Context) {
+	for {
+		b.mu.Lock()
+		browserCtx := b.ctx
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-browserCtx.Done():
+		}
+
+		// Подключение закрыто при остановке приложения.
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("browser: connection lost, reconnecting\n")
+
+		b.setErr(errConnectionLost)
+
+		for attempt := 0; ; attempt++ {
+			err := b.reconnect()
+			if err == nil {
+				log.Printf("browser: reconnected after %d attempts\n", attempt+1)
+				break
+			}
+
+			b.setErr(err)
+
+			delay := reconnectDelay(attempt)
+			log.Printf("browser: unable to reconnect, next attempt in %s: %v\n", delay, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}
+}
+
+// reconnect закрывает прежнее подключение и открывает новое.
+func (b *Browser) reconnect() error {
+	b.mu.Lock()
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.mu.Unlock()
+
+	ctx, cancel := b.connect()
+
+	// Первый запуск подключается к браузеру и открывает вкладку.
+	if err := Run(ctx); err != nil {
+		cancel()
+		return fmt.Errorf("browser: unable to connect: %v", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		cancel()
+		return errClosed
+	}
+
+	b.ctx, b.cancel, b.err = ctx, cancel, nil
+
+	return nil
+}
+
+func (b *Browser) setErr(err error) {
+	b.mu.Lock()
+	b.err = err
+	b.mu.Unlock()
+}
+
+// reconnectDelay пауза перед попыткой attempt, начиная с нулевой:
+// удваивается от reconnectMinDelay до reconnectMaxDelay.
+func reconnectDelay(attempt int) time.Duration {
+	delay := reconnectMinDelay
+	for i := 0; i < attempt && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > reconnectMaxDelay {
+		return reconnectMaxDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,53 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 1, want: 2 * time.Second},
+		{attempt: 3, want: 8 * time.Second},
+		{attempt: 5, want: 32 * time.Second},
+		{attempt: 6, want: time.Minute},
+		{attempt: 100, want: time.Minute},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.want.String(), func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got := reconnectDelay(tc.attempt)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestBrowserHealth(t *testing.T) {
+	t.Parallel()
+
+	b := NewRemoteBrowser("ws://127.0.0.1:9222")
+
+	// act & assert
+	require.ErrorIs(t, b.Health(), ErrUnavailable)
+
+	b.setErr(nil)
+	require.NoError(t, b.Health())
+
+	b.Close()
+	require.ErrorIs(t, b.Health(), ErrUnavailable)
+}
@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/denisdubovitskiy/feedparser/internal/fetcher"
+)
+
+// Fetcher загружает страницы браузером во вкладках пула. Нужен страницам,
+// которые строятся скриптами, статические страницы быстрее загружать
+// через fetcher.HTTPFetcher.
+type Fetcher struct {
+	pool *Pool
+	opts Options
+}
+
+var _ fetcher.Fetcher = (*Fetcher)(nil)
+
+func NewFetcher(pool *Pool) *Fetcher {
+	return &Fetcher{pool: pool}
+}
+
+// WithOptions возвращает загрузчик с условиями готовности и действиями
+// на странице из opts.
+func (f *Fetcher) WithOptions(opts Options) *Fetcher {
+	return &Fetcher{pool: f.pool, opts: opts}
+}
+
+// Fetch открывает страницу в браузере и возвращает её HTML после
+// рендеринга. Отмена ctx или истечение его срока прерывают загрузку.
+func (f *Fetcher) Fetch(ctx context.Context, url string) (string, error) {
+	var body string
+
+	err := f.pool.Do(ctx, func(tabCtx context.Context) error {
+		var err error
+		body, err = FetchHTML(tabCtx, url, f.opts)
+		return err
+	})
+	if err != nil {
+		// Условие, не выполнившееся вовремя, и недоступность браузера
+		// сообщаются как есть.
+		if errors.Is(err, ErrWaitTimeout) || errors.Is(err, ErrUnavailable) {
+			return "", fmt.Errorf("browser: unable to fetch %s: %w", url, err)
+		}
+
+		// Ошибка chromedp при отмене не говорит, что загрузка прервана по сроку.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("browser: unable to fetch %s: %w", url, ctxErr)
+		}
+		return "", fmt.Errorf("browser: unable to fetch %s: %v", url, err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+// Options настройки загрузки страницы в браузере.
+type Options struct {
+	// Waits условия готовности страницы, по умолчанию DefaultWaits.
+	Waits []Wait
+	// Actions действия на странице после выполнения условий готовности.
+	Actions []Action
+}
+
+// NewOptions разбирает настройки загрузки из конфигурации источника.
+func NewOptions(conf config.SourceConfig) (Options, error) {
+	waits, err := NewWaits(conf.Wait)
+	if err != nil {
+		return Options{}, err
+	}
+
+	actions, err := NewActions(conf.Actions)
+	if err != nil {
+		return Options{}, err
+	}
+
+	return Options{Waits: waits, Actions: actions}, nil
+}
+
+// Duration наибольшее время ожидания условий и выполнения действий.
+func (o Options) Duration() time.Duration {
+	return WaitsDuration(o.Waits) + ActionsDuration(o.Actions)
+}
+
+// actions действия на странице в порядке из конфигурации.
+func (o Options) actions(url string) chromedp.Tasks {
+	tasks := make(chromedp.Tasks, 0, len(o.Actions))
+	for _, a := range o.Actions {
+		tasks = append(tasks, a.action(url))
+	}
+	return tasks
+}
@@ -0,0 +1,165 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Pool набор вкладок браузера для одновременной загрузки страниц.
+// Вкладка закрывается и открывается заново после maxUses загрузок
+// или после ошибки, чтобы ограничить память, занятую страницами.
+type Pool struct {
+	browser *Browser
+	maxUses int
+	tabs    chan *tab
+
+	mu     sync.Mutex
+	closed bool
+}
+
+type tab struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	uses   int
+}
+
+// NewPool создаёт пул из size вкладок. Вкладки открываются при первом
+// обращении. maxUses <= 0 отключает пересоздание вкладок по числу загрузок.
+func NewPool(browser *Browser, size, maxUses int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+
+	p := &Pool{
+		browser: browser,
+		maxUses: maxUses,
+		tabs:    make(chan *tab, size),
+	}
+
+	// Пустые слоты: вкладка открывается, когда слот впервые понадобится.
+	for i := 0; i < size; i++ {
+		p.tabs <- nil
+	}
+
+	return p
+}
+
+// Size количество вкладок пула.
+func (p *Pool) Size() int {
+	return cap(p.tabs)
+}
+
+// Do выполняет f в свободной вкладке, ожидая её освобождения. Контекст
+// tabCtx отменяется вместе с ctx. Вкладка, в которой f вернула ошибку,
+// закрывается: страница могла остаться в неизвестном состоянии.
+func (p *Pool) Do(ctx context.Context, f func(tabCtx context.Context) error) error {
+	var t *tab
+
+	select {
+	case t = <-p.tabs:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Вкладки прежнего подключения к браузеру закрыты вместе с ним.
+	if t != nil && t.ctx.Err() != nil {
+		t = nil
+	}
+
+	if t == nil {
+		var err error
+		if t, err = p.openTab(); err != nil {
+			p.tabs <- nil
+			return err
+		}
+	}
+
+	tabCtx, cancel := context.WithCancel(t.ctx)
+	stop := context.AfterFunc(ctx, cancel)
+
+	err := f(tabCtx)
+
+	stop()
+	cancel()
+
+	// Вкладка закрылась не по нашей отмене: подключение к браузеру потеряно.
+	if err != nil && t.ctx.Err() != nil && ctx.Err() == nil {
+		err = fmt.Errorf("browser: %w: %v", ErrUnavailable, err)
+	}
+
+	t.uses++
+	p.release(t, err != nil)
+
+	return err
+}
+
+// Close закрывает свободные вкладки. Занятые вкладки закрываются
+// при возврате в пул.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+
+	var idle int
+
+	for drained := false; !drained; {
+		select {
+		case t := <-p.tabs:
+			if t != nil {
+				t.cancel()
+			}
+			idle++
+		default:
+			drained = true
+		}
+	}
+
+	for i := 0; i < idle; i++ {
+		p.tabs <- nil
+	}
+}
+
+func (p *Pool) openTab() (*tab, error) {
+	browserCtx, err := p.browser.context()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := chromedp.NewContext(browserCtx)
+	chromedp.ListenTarget(ctx, disableFetchExceptScripts(ctx))
+
+	// Первый запуск открывает вкладку, она живёт до отмены ctx.
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("browser: unable to open a tab: %v", err)
+	}
+
+	return &tab{ctx: ctx, cancel: cancel}, nil
+}
+
+func (p *Pool) release(t *tab, failed bool) {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+
+	recycle := failed || closed || (p.maxUses > 0 && t.uses >= p.maxUses)
+	if !recycle {
+		p.tabs <- t
+		return
+	}
+
+	switch {
+	case closed:
+	case failed:
+		log.Printf("browser: recycling a tab after a failed navigation\n")
+	default:
+		log.Printf("browser: recycling a tab after %d navigations\n", t.uses)
+	}
+
+	t.cancel()
+	p.tabs <- nil
+}
@@ -0,0 +1,166 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPool создаёт пул из одной уже открытой вкладки без браузера.
+func newTestPool(maxUses int) (*Pool, *int) {
+	closed := new(int)
+
+	p := &Pool{maxUses: maxUses, tabs: make(chan *tab, 1)}
+	p.tabs <- &tab{ctx: context.Background(), cancel: func() { *closed++ }}
+
+	return p, closed
+}
+
+func TestPoolDo(t *testing.T) {
+	t.Parallel()
+
+	errPage := errors.New("page crashed")
+
+	cases := []struct {
+		name       string
+		maxUses    int
+		results    []error
+		wantClosed int
+		wantReused bool
+	}{
+		{
+			name:       "reused below max uses",
+			maxUses:    3,
+			results:    []error{nil, nil},
+			wantReused: true,
+		},
+		{
+			name:       "recycled after max uses",
+			maxUses:    2,
+			results:    []error{nil, nil},
+			wantClosed: 1,
+		},
+		{
+			name:       "recycled after error",
+			maxUses:    0,
+			results:    []error{errPage},
+			wantClosed: 1,
+		},
+		{
+			name:       "unlimited uses",
+			maxUses:    0,
+			results:    []error{nil, nil, nil, nil},
+			wantReused: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, closed := newTestPool(tc.maxUses)
+
+			// act
+			for _, result := range tc.results {
+				err := p.Do(context.Background(), func(context.Context) error { return result })
+				require.ErrorIs(t, err, result)
+			}
+
+			// assert
+			require.Equal(t, tc.wantClosed, *closed)
+
+			reused := <-p.tabs
+			require.Equal(t, tc.wantReused, reused != nil)
+		})
+	}
+}
+
+func TestPoolDoCancelled(t *testing.T) {
+	t.Parallel()
+
+	p, _ := newTestPool(0)
+	busy := <-p.tabs
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	err := p.Do(ctx, func(context.Context) error { return nil })
+
+	// assert
+	require.ErrorIs(t, err, context.Canceled)
+
+	p.tabs <- busy
+}
+
+func TestPoolDoUnavailable(t *testing.T) {
+	t.Parallel()
+
+	p := NewPool(NewRemoteBrowser("ws://127.0.0.1:9222"), 1, 0)
+	called := false
+
+	// act
+	err := p.Do(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	})
+
+	// assert
+	require.ErrorIs(t, err, ErrUnavailable)
+	require.False(t, called)
+	require.Nil(t, <-p.tabs)
+}
+
+func TestPoolDoDeadTab(t *testing.T) {
+	t.Parallel()
+
+	// Вкладка прежнего подключения: его контекст уже отменён.
+	dead, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := NewPool(NewRemoteBrowser("ws://127.0.0.1:9222"), 1, 0)
+	<-p.tabs
+	p.tabs <- &tab{ctx: dead, cancel: cancel}
+
+	// act
+	err := p.Do(context.Background(), func(context.Context) error { return nil })
+
+	// assert
+	require.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestPoolDoBrowserLost(t *testing.T) {
+	t.Parallel()
+
+	browserCtx, lose := context.WithCancel(context.Background())
+
+	p, _ := newTestPool(0)
+	<-p.tabs
+	p.tabs <- &tab{ctx: browserCtx, cancel: lose}
+
+	// act
+	err := p.Do(context.Background(), func(tabCtx context.Context) error {
+		lose()
+		return tabCtx.Err()
+	})
+
+	// assert
+	require.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestPoolClose(t *testing.T) {
+	t.Parallel()
+
+	p, closed := newTestPool(0)
+
+	// act
+	p.Close()
+
+	// assert
+	require.Equal(t, 1, *closed)
+	require.Nil(t, <-p.tabs)
+}
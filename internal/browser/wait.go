@@ -0,0 +1,206 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+// defaultWaitTimeout предельное время ожидания условия по умолчанию.
+const defaultWaitTimeout = 10 * time.Second
+
+// ErrWaitTimeout условие готовности страницы не выполнилось вовремя.
+var ErrWaitTimeout = errors.New("wait timed out")
+
+// DefaultWaits ожидание для источников без условий: пауза в одну секунду.
+var DefaultWaits = []Wait{{Delay: time.Second}}
+
+// Wait условие готовности страницы после загрузки. Задано ровно одно
+// из условий: Selector, NetworkIdle, Expression или Delay.
+type Wait struct {
+	Selector    string
+	NetworkIdle bool
+	Expression  string
+	Delay       time.Duration
+	// Timeout предельное время ожидания, для Delay не используется.
+	Timeout time.Duration
+}
+
+// NewWaits разбирает условия готовности из конфигурации источника.
+func NewWaits(rules []config.WaitRule) ([]Wait, error) {
+	waits := make([]Wait, 0, len(rules))
+
+	for i, rule := range rules {
+		w := Wait{
+			Selector:    strings.TrimSpace(rule.Selector),
+			NetworkIdle: rule.NetworkIdle,
+			Expression:  strings.TrimSpace(rule.Expression),
+			Timeout:     defaultWaitTimeout,
+		}
+
+		var err error
+
+		if len(rule.Delay) > 0 {
+			if w.Delay, err = time.ParseDuration(rule.Delay); err != nil || w.Delay <= 0 {
+				return nil, fmt.Errorf("wait %d: invalid delay %q", i, rule.Delay)
+			}
+		}
+
+		if len(rule.Timeout) > 0 {
+			if w.Timeout, err = time.ParseDuration(rule.Timeout); err != nil || w.Timeout <= 0 {
+				return nil, fmt.Errorf("wait %d: invalid timeout %q", i, rule.Timeout)
+			}
+		}
+
+		conditions := 0
+		for _, set := range []bool{len(w.Selector) > 0, w.NetworkIdle, len(w.Expression) > 0, w.Delay > 0} {
+			if set {
+				conditions++
+			}
+		}
+
+		if conditions != 1 {
+			return nil, fmt.Errorf("wait %d: exactly one of selector, network_idle, expression or delay is required", i)
+		}
+
+		waits = append(waits, w)
+	}
+
+	return waits, nil
+}
+
+// WaitsDuration наибольшее суммарное время ожидания условий waits.
+func WaitsDuration(waits []Wait) time.Duration {
+	if len(waits) == 0 {
+		waits = DefaultWaits
+	}
+
+	var total time.Duration
+	for _, w := range waits {
+		if w.Delay > 0 {
+			total += w.Delay
+			continue
+		}
+		total += w.Timeout
+	}
+
+	return total
+}
+
+func (w Wait) String() string {
+	switch {
+	case len(w.Selector) > 0:
+		return fmt.Sprintf("selector %q", w.Selector)
+	case w.NetworkIdle:
+		return "network idle"
+	case len(w.Expression) > 0:
+		return fmt.Sprintf("expression %q", w.Expression)
+	}
+	return fmt.Sprintf("delay %s", w.Delay)
+}
+
+// action ожидает условие. Истечение срока ожидания возвращает
+// ErrWaitTimeout с описанием условия, отмена ctx - ошибку контекста.
+func (w Wait) action(idle <-chan struct{}) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if w.Delay > 0 {
+			return chromedp.Sleep(w.Delay).Do(ctx)
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+
+		var err error
+
+		switch {
+		case len(w.Selector) > 0:
+			err = chromedp.WaitVisible(w.Selector, chromedp.ByQuery).Do(waitCtx)
+		case len(w.Expression) > 0:
+			var res interface{}
+			err = chromedp.Poll(
+				w.Expression,
+				&res,
+				chromedp.WithPollingInterval(100*time.Millisecond),
+				chromedp.WithPollingTimeout(w.Timeout),
+			).Do(waitCtx)
+		case w.NetworkIdle:
+			select {
+			case <-idle:
+			case <-waitCtx.Done():
+				err = waitCtx.Err()
+			}
+		}
+
+		// Истёк срок или отменена вся загрузка страницы, а не только условие.
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		if errors.Is(err, chromedp.ErrPollingTimeout) || errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %s after %s", ErrWaitTimeout, w, w.Timeout)
+		}
+
+		return fmt.Errorf("wait %s: %v", w, err)
+	})
+}
+
+// navigate переходит на страницу и ожидает условия готовности по порядку.
+// Событие простоя сети отслеживается с момента перехода, поэтому слушатель
+// регистрируется заранее и снимается вызовом cancel.
+func navigate(ctx context.Context, url string, waits []Wait) (chromedp.Tasks, context.CancelFunc) {
+	if len(waits) == 0 {
+		waits = DefaultWaits
+	}
+
+	listenCtx, cancel := context.WithCancel(ctx)
+
+	var idle <-chan struct{}
+	for _, w := range waits {
+		if w.NetworkIdle {
+			idle = listenNetworkIdle(listenCtx)
+			break
+		}
+	}
+
+	tasks := chromedp.Tasks{chromedp.Navigate(url)}
+	for _, w := range waits {
+		tasks = append(tasks, w.action(idle))
+	}
+
+	return tasks, cancel
+}
+
+// listenNetworkIdle закрывает канал, когда Chrome сообщает о простое сети
+// для документа, загруженного в главный фрейм после начала прослушивания.
+func listenNetworkIdle(ctx context.Context) <-chan struct{} {
+	idle := make(chan struct{})
+
+	var (
+		once   sync.Once
+		loader cdp.LoaderID
+	)
+
+	chromedp.ListenTarget(ctx, func(event interface{}) {
+		switch ev := event.(type) {
+		case *page.EventFrameNavigated:
+			if ev.Frame != nil && len(ev.Frame.ParentID) == 0 {
+				loader = ev.Frame.LoaderID
+			}
+		case *page.EventLifecycleEvent:
+			if ev.Name == "networkIdle" && len(loader) > 0 && ev.LoaderID == loader {
+				once.Do(func() { close(idle) })
+			}
+		}
+	})
+
+	return idle
+}
@@ -0,0 +1,154 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestNewWaits(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		rules   []config.WaitRule
+		want    []Wait
+		wantErr bool
+	}{
+		{
+			name:  "default timeout",
+			rules: []config.WaitRule{{Selector: " article.post "}},
+			want:  []Wait{{Selector: "article.post", Timeout: 10 * time.Second}},
+		},
+		{
+			name: "all conditions in order",
+			rules: []config.WaitRule{
+				{NetworkIdle: true, Timeout: "15s"},
+				{Expression: "window.__ready === true", Timeout: "3s"},
+				{Delay: "500ms"},
+			},
+			want: []Wait{
+				{NetworkIdle: true, Timeout: 15 * time.Second},
+				{Expression: "window.__ready === true", Timeout: 3 * time.Second},
+				{Delay: 500 * time.Millisecond, Timeout: 10 * time.Second},
+			},
+		},
+		{
+			name:    "no condition",
+			rules:   []config.WaitRule{{Timeout: "5s"}},
+			wantErr: true,
+		},
+		{
+			name:    "two conditions",
+			rules:   []config.WaitRule{{Selector: "article", NetworkIdle: true}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			rules:   []config.WaitRule{{Selector: "article", Timeout: "soon"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative delay",
+			rules:   []config.WaitRule{{Delay: "-1s"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got, err := NewWaits(tc.rules)
+
+			// assert
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestWaitsDuration(t *testing.T) {
+	t.Parallel()
+
+	waits := []Wait{
+		{Selector: "article", Timeout: 5 * time.Second},
+		{Delay: 500 * time.Millisecond, Timeout: 10 * time.Second},
+	}
+
+	// act
+	got := WaitsDuration(waits)
+
+	// assert
+	require.Equal(t, 5500*time.Millisecond, got)
+	require.Equal(t, time.Second, WaitsDuration(nil))
+}
+
+func TestWaitString(t *testing.T) {
+	t.Parallel()
+
+	// act & assert
+	require.Equal(t, `selector "article"`, Wait{Selector: "article"}.String())
+	require.Equal(t, "network idle", Wait{NetworkIdle: true}.String())
+	require.Equal(t, `expression "window.ready"`, Wait{Expression: "window.ready"}.String())
+	require.Equal(t, "delay 1s", Wait{Delay: time.Second}.String())
+}
+
+func TestWaitActionTimeout(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		waitTimeout time.Duration
+		pageTimeout time.Duration
+		wantErr     error
+		wantTimeout bool
+	}{
+		{
+			name:        "wait rule deadline",
+			waitTimeout: 10 * time.Millisecond,
+			pageTimeout: time.Minute,
+			wantErr:     ErrWaitTimeout,
+			wantTimeout: true,
+		},
+		{
+			name:        "page deadline",
+			waitTimeout: time.Minute,
+			pageTimeout: 10 * time.Millisecond,
+			wantErr:     context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.pageTimeout)
+			defer cancel()
+
+			wait := Wait{NetworkIdle: true, Timeout: tc.waitTimeout}
+
+			// act
+			err := wait.action(make(chan struct{})).Do(ctx)
+
+			// assert
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantTimeout, errors.Is(err, ErrWaitTimeout))
+		})
+	}
+}
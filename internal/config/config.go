@@ -2,16 +2,254 @@ package config
 
 import "gopkg.in/yaml.v3"
 
+// Типы источников.
+const (
+	// TypeHTML статьи извлекаются CSS-селекторами из страницы,
+	// отрендеренной браузером. Используется, если тип не указан.
+	TypeHTML = "html"
+	// TypeRSS лента RSS 2.0 или RSS 1.0 (RDF).
+	TypeRSS = "rss"
+	// TypeAtom лента Atom.
+	TypeAtom = "atom"
+	// TypeJSONFeed лента в формате https://jsonfeed.org.
+	TypeJSONFeed = "jsonfeed"
+	// TypeJSON ответ JSON API, статьи извлекаются выражениями JSONPath.
+	TypeJSON = "json"
+	// TypeSitemap карта сайта sitemap.xml или индекс карт сайта.
+	TypeSitemap = "sitemap"
+	// TypeWatch страница без списка статей: журнал изменений, цены.
+	// Изменение выбранной области публикуется как статья.
+	TypeWatch = "watch"
+)
+
+// Языки селекторов.
+const (
+	// SelectorCSS используется, если язык не указан.
+	SelectorCSS   = "css"
+	SelectorXPath = "xpath"
+	// SelectorJSONPath используется для источников TypeJSON.
+	SelectorJSONPath = "jsonpath"
+)
+
+// Способы извлечения статей со страницы.
+const (
+	// ExtractSelectors статьи извлекаются селекторами.
+	// Используется, если способ не указан.
+	ExtractSelectors = "selectors"
+	// ExtractJSONLD статьи извлекаются из разметки schema.org
+	// (JSON-LD и microdata), селекторы используются, если разметки нет.
+	ExtractJSONLD = "jsonld"
+)
+
 type SourceConfig struct {
+	// Type тип источника, по умолчанию TypeHTML.
+	Type string `yaml:"type" json:"type"`
+	// FeedURL адрес ленты, если он отличается от адреса источника.
+	FeedURL string `yaml:"feed_url" json:"feed_url"`
+	// APIURL адрес JSON API источника TypeJSON, если он отличается
+	// от адреса источника. Ссылки на статьи разрешаются относительно
+	// адреса источника.
+	APIURL string `yaml:"api_url" json:"api_url"`
+	// Sitemap настройки источника TypeSitemap.
+	Sitemap SitemapRules `yaml:"sitemap" json:"sitemap"`
+	// Watch настройки источника TypeWatch.
+	Watch WatchRules `yaml:"watch" json:"watch"`
+	// Capture шаблон адреса ответа XHR/fetch со списком статей, например
+	// "https://example.com/api/posts*". Если задан, страница загружается
+	// браузером, а статьи извлекаются из перехваченных ответов выражениями
+	// JSONPath, как у источников TypeJSON.
+	Capture string `yaml:"capture" json:"capture"`
+	// Render загружать страницу браузером, по умолчанию true. Статические
+	// сайты с render: false загружаются обычным HTTP-запросом без запуска
+	// скриптов. Ленты, карты сайта и JSON API всегда загружаются без браузера,
+	// источники с Capture - всегда браузером.
+	Render *bool `yaml:"render" json:"render"`
+	// Wait условия готовности страницы в браузере, проверяются по порядку.
+	// По умолчанию - пауза в одну секунду после загрузки.
+	Wait []WaitRule `yaml:"wait" json:"wait"`
+	// Actions действия на странице в браузере после выполнения условий
+	// готовности: прокрутка, нажатие кнопок "Показать ещё", закрытие окон.
+	Actions []ActionRule `yaml:"actions" json:"actions"`
+	// Extract способ извлечения статей, по умолчанию ExtractSelectors.
+	Extract string `yaml:"extract" json:"extract"`
+	// SelectorType язык селекторов, по умолчанию SelectorCSS.
+	SelectorType    string `yaml:"selector_type" json:"selector_type"`
 	ArticleSelector string `yaml:"article" json:"article"`
 	TitleSelector   string `yaml:"title" json:"title"`
 	DetailSelector  string `yaml:"detail" json:"detail"`
+	// Необязательные селекторы метаданных внутри карточки статьи.
+	DateSelector    string `yaml:"date" json:"date"`
+	AuthorSelector  string `yaml:"author" json:"author"`
+	SummarySelector string `yaml:"summary" json:"summary"`
+	ImageSelector   string `yaml:"image" json:"image"`
+	// DateFormat формат даты карточки в нотации Go, например "02.01.2006 15:04".
+	// Пробуется первым, затем - распространённые форматы.
+	DateFormat string `yaml:"date_format" json:"date_format"`
+	// DateLocale язык названий месяцев и относительных дат ("ru", "en").
+	// По умолчанию пробуются все поддерживаемые языки.
+	DateLocale string `yaml:"date_locale" json:"date_locale"`
+	// NextSelector селектор ссылки на следующую страницу списка.
+	NextSelector string `yaml:"next" json:"next"`
+	// PageURLTemplate шаблон адреса страницы списка, если ссылки на
+	// следующую страницу нет, например "https://habr.com/ru/hub/go/page{page}/".
+	PageURLTemplate string `yaml:"page_url" json:"page_url"`
+	// MaxPages максимальное количество страниц списка за один обход.
+	MaxPages int `yaml:"max_pages" json:"max_pages"`
+	// Enrich загружать страницы новых статей и дополнять их
+	// метаданными OpenGraph.
+	Enrich bool `yaml:"enrich" json:"enrich"`
+	// Include правила отбора статей: если заданы, сохраняются только
+	// статьи, подходящие хотя бы под одно из них.
+	Include FilterRules `yaml:"include" json:"include"`
+	// Exclude правила исключения статей, имеют приоритет перед Include.
+	Exclude FilterRules `yaml:"exclude" json:"exclude"`
+	// URLRules правила нормализации адресов статей.
+	URLRules URLRules `yaml:"url_rules" json:"url_rules"`
+	// Rewrite правила переписывания заголовков и адресов статей.
+	Rewrite RewriteRules `yaml:"rewrite" json:"rewrite"`
 	// Tags теги, которые будут отрисованы в сообщении.
 	Tags []string `yaml:"tags"`
 	// Channel переопределяет канал для отсылки.
 	Channels []string `yaml:"channels"`
 }
 
+// FilterRules правила фильтрации статей по заголовку и адресу.
+// Правило - ключевое слово без учёта регистра или регулярное
+// выражение с префиксом "re:", например "re:(?i)^kubernetes".
+type FilterRules struct {
+	Title []string `yaml:"title" json:"title"`
+	URL   []string `yaml:"url" json:"url"`
+}
+
+// RewriteRules правила переписывания заголовков и адресов статей.
+// Правила применяются по порядку, каждое - к результату предыдущего.
+type RewriteRules struct {
+	Title []RewriteRule `yaml:"title" json:"title"`
+	URL   []RewriteRule `yaml:"url" json:"url"`
+}
+
+// RewriteRule заменяет совпадения регулярного выражения Pattern на Replace
+// (поддерживаются группы $1, ${name}). Для адреса вместо замены можно
+// задать Unwrap - параметр запроса адреса-редиректора с настоящим адресом
+// статьи, например "url"; Pattern тогда ограничивает, к каким адресам
+// применяется правило.
+type RewriteRule struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Replace string `yaml:"replace" json:"replace"`
+	Unwrap  string `yaml:"unwrap" json:"unwrap"`
+}
+
+// URLRules правила нормализации адресов статей перед сохранением.
+// Параметры задаются именем или префиксом со звёздочкой: "utm_*".
+type URLRules struct {
+	// Keep параметры запроса, которые нужно сохранить. Если список
+	// задан, остальные параметры удаляются.
+	Keep []string `yaml:"keep" json:"keep"`
+	// Drop параметры, удаляемые в дополнение к параметрам отслеживания.
+	Drop []string `yaml:"drop" json:"drop"`
+	// StripTrailingSlash удалять завершающий слеш пути. Включается для
+	// сайтов, которые отдают статью по обоим адресам.
+	StripTrailingSlash bool `yaml:"strip_trailing_slash" json:"strip_trailing_slash"`
+	// ForceHTTPS заменять http на https для сайтов, доступных по обеим схемам.
+	ForceHTTPS bool `yaml:"force_https" json:"force_https"`
+}
+
+// SitemapRules настройки обхода карты сайта.
+type SitemapRules struct {
+	// URL адрес карты сайта или индекса, по умолчанию /sitemap.xml
+	// на хосте источника.
+	URL string `yaml:"url" json:"url"`
+	// Pattern регулярное выражение, которому должен соответствовать
+	// адрес статьи, например '^https://dropbox\.tech/infrastructure/'.
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// Days окно в днях по дате lastmod, по умолчанию 30 дней.
+	// Записи без даты в окно не попадают.
+	Days int `yaml:"days" json:"days"`
+}
+
+// WatchRules настройки отслеживания изменений страницы.
+type WatchRules struct {
+	// Selector область страницы, изменения которой отслеживаются,
+	// по умолчанию - всё тело страницы.
+	Selector string `yaml:"selector" json:"selector"`
+	// Ignore элементы области, изменения которых не учитываются:
+	// счётчики, даты, баннеры.
+	Ignore []string `yaml:"ignore" json:"ignore"`
+	// Title заголовок статьи об изменении, по умолчанию - заголовок страницы.
+	Title string `yaml:"title" json:"title"`
+}
+
+// WaitRule условие готовности страницы в браузере. Задаётся ровно одно
+// из условий. Длительности записываются как "500ms", "10s".
+type WaitRule struct {
+	// Selector CSS-селектор элемента, который должен стать видимым.
+	Selector string `yaml:"selector" json:"selector"`
+	// NetworkIdle ждать, пока страница полсекунды не выполняет запросов.
+	NetworkIdle bool `yaml:"network_idle" json:"network_idle"`
+	// Expression выражение JavaScript, которое должно стать истинным.
+	Expression string `yaml:"expression" json:"expression"`
+	// Delay фиксированная пауза.
+	Delay string `yaml:"delay" json:"delay"`
+	// Timeout предельное время ожидания условия, по умолчанию 10s.
+	Timeout string `yaml:"timeout" json:"timeout"`
+}
+
+// ActionRule действие на странице в браузере. Задаётся ровно одно
+// из действий: Scroll, Click, Wait, Press или Evaluate.
+type ActionRule struct {
+	// Scroll сколько раз прокрутить страницу до конца.
+	Scroll int `yaml:"scroll" json:"scroll"`
+	// Click CSS-селектор элемента, по которому нужно нажать.
+	Click string `yaml:"click" json:"click"`
+	// Wait условие, которого нужно дождаться, например появления
+	// подгруженных карточек.
+	Wait *WaitRule `yaml:"wait" json:"wait"`
+	// Press клавиша: один символ или название, например "Escape", "End".
+	Press string `yaml:"press" json:"press"`
+	// Evaluate выражение JavaScript, результат которого не используется.
+	Evaluate string `yaml:"evaluate" json:"evaluate"`
+	// Optional ошибка действия не прерывает загрузку страницы: окна
+	// cookies и подписки показываются не всегда.
+	Optional bool `yaml:"optional" json:"optional"`
+}
+
+// IsRendered сообщает, что страница источника загружается браузером.
+func (c SourceConfig) IsRendered() bool {
+	return c.Render == nil || *c.Render || len(c.Capture) > 0
+}
+
+// NeedsBrowser сообщает, что страницы источника загружаются в браузере.
+// Ленты, карты сайта и JSON API всегда загружаются по HTTP.
+func (c SourceConfig) NeedsBrowser() bool {
+	if c.IsFeed() || c.Type == TypeSitemap || c.Type == TypeJSON {
+		return false
+	}
+	return c.IsRendered()
+}
+
+// IsFeed сообщает, что источник является лентой и не требует браузера.
+func (c SourceConfig) IsFeed() bool {
+	switch c.Type {
+	case TypeRSS, TypeAtom, TypeJSONFeed:
+		return true
+	}
+	return false
+}
+
+// IsHTML сообщает, что статьи извлекаются из страницы, отрендеренной браузером.
+func (c SourceConfig) IsHTML() bool {
+	return c.Type == "" || c.Type == TypeHTML
+}
+
+// IsKnownType сообщает, что тип источника - один из поддерживаемых.
+func (c SourceConfig) IsKnownType() bool {
+	switch c.Type {
+	case "", TypeHTML, TypeRSS, TypeAtom, TypeJSONFeed, TypeJSON, TypeSitemap, TypeWatch:
+		return true
+	}
+	return false
+}
+
 type Source struct {
 	Name   string       `yaml:"name" json:"name"`
 	URL    string       `yaml:"url" json:"url"`
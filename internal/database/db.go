@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	_ "embed"
 
@@ -33,7 +34,55 @@ func Open(name string) (*sql.DB, error) {
 //go:embed migration.sql
 var migration string
 
+// column колонка, добавленная в таблицу после её создания.
+type column struct {
+	table      string
+	name       string
+	definition string
+}
+
+// addedColumns колонки, появившиеся после первой версии схемы.
+// В новых базах они создаются migration.sql, в существующих -
+// добавляются при миграции, если ещё отсутствуют.
+var addedColumns = []column{
+	{table: "articles", name: "published", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "articles", name: "author", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "articles", name: "summary", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "articles", name: "image", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "articles", name: "canonical_url", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "sources", name: "broken", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "crawls", name: "run", definition: "INTEGER NOT NULL DEFAULT 0"},
+}
+
 func Migrate(ctx context.Context, db *sql.DB) error {
-	_, err := db.ExecContext(ctx, migration)
-	return err
+	if _, err := db.ExecContext(ctx, migration); err != nil {
+		return err
+	}
+
+	for _, c := range addedColumns {
+		if err := addColumn(ctx, db, c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addColumn(ctx context.Context, db *sql.DB, c column) error {
+	var count int
+	row := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.name)
+	if err := row.Scan(&count); err != nil {
+		return fmt.Errorf("database: unable to inspect table %s: %v", c.table, err)
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	stmt := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, c.table, c.name, c.definition)
+	if _, err := db.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("database: unable to add column %s.%s: %v", c.table, c.name, err)
+	}
+
+	return nil
 }
@@ -7,19 +7,61 @@ CREATE TABLE IF NOT EXISTS sources
     name         TEXT                NOT NULL UNIQUE DEFAULT '',
     config       TEXT                NOT NULL        DEFAULT '',
     last_visited INTEGER             NOT NULL        DEFAULT 0,
-    retries      INTEGER             NOT NULL        DEFAULT 0
+    retries      INTEGER             NOT NULL        DEFAULT 0,
+    broken       INTEGER             NOT NULL        DEFAULT 0
 );
 
 CREATE TABLE IF NOT EXISTS articles
+(
+    id            INTEGER PRIMARY KEY NOT NULL        DEFAULT 0,
+    source_id     INTEGER             NOT NULL        DEFAULT 0,
+    title         TEXT                NOT NULL        DEFAULT '',
+    url           TEXT                NOT NULL UNIQUE DEFAULT '',
+    sent          INTEGER             NOT NULL        DEFAULT 0,
+    added         INTEGER             NOT NULL        DEFAULT 0,
+    published     INTEGER             NOT NULL        DEFAULT 0,
+    author        TEXT                NOT NULL        DEFAULT '',
+    summary       TEXT                NOT NULL        DEFAULT '',
+    image         TEXT                NOT NULL        DEFAULT '',
+    canonical_url TEXT                NOT NULL        DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS filtered_articles
 (
     id        INTEGER PRIMARY KEY NOT NULL        DEFAULT 0,
     source_id INTEGER             NOT NULL        DEFAULT 0,
     title     TEXT                NOT NULL        DEFAULT '',
     url       TEXT                NOT NULL UNIQUE DEFAULT '',
-    sent      INTEGER             NOT NULL        DEFAULT 0,
+    reason    TEXT                NOT NULL        DEFAULT '',
     added     INTEGER             NOT NULL        DEFAULT 0
 );
 
+CREATE TABLE IF NOT EXISTS crawls
+(
+    id        INTEGER PRIMARY KEY NOT NULL DEFAULT 0,
+    source_id INTEGER             NOT NULL DEFAULT 0,
+    started   INTEGER             NOT NULL DEFAULT 0,
+    finished  INTEGER             NOT NULL DEFAULT 0,
+    pages     INTEGER             NOT NULL DEFAULT 0,
+    matched   INTEGER             NOT NULL DEFAULT 0,
+    accepted  INTEGER             NOT NULL DEFAULT 0,
+    rejected  INTEGER             NOT NULL DEFAULT 0,
+    saved     INTEGER             NOT NULL DEFAULT 0,
+    reasons   TEXT                NOT NULL DEFAULT '',
+    error     TEXT                NOT NULL DEFAULT '',
+    run       INTEGER             NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS page_snapshots
+(
+    id        INTEGER PRIMARY KEY NOT NULL DEFAULT 0,
+    source_id INTEGER             NOT NULL DEFAULT 0,
+    hash      TEXT                NOT NULL DEFAULT '',
+    text      TEXT                NOT NULL DEFAULT '',
+    added     INTEGER             NOT NULL DEFAULT 0,
+    checked   INTEGER             NOT NULL DEFAULT 0
+);
+
 CREATE TABLE IF NOT EXISTS timestamp (
     timestamp INTEGER NOT NULL default 0
 );
@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+	"github.com/denisdubovitskiy/feedparser/internal/database/queries"
+)
+
+// NormalizeResult итог нормализации адресов сохранённых статей.
+type NormalizeResult struct {
+	// Updated статьи, адрес которых изменился.
+	Updated int
+	// Merged удалённые повторы статей.
+	Merged int
+}
+
+// normalizedArticle статья и адрес, к которому она приводится.
+type normalizedArticle struct {
+	row  queries.SelectArticleURLsRow
+	url  string
+	sent int64
+}
+
+// NormalizeArticleURLs приводит адреса сохранённых статей к виду,
+// который возвращает normalize, и объединяет статьи с совпавшими
+// адресами. Из повторов остаётся отправленная статья, а если таких
+// нет - самая ранняя. Если адрес уже занят статьёй другого источника,
+// приводимая статья считается её повтором и удаляется.
+// При dryRun изменения не сохраняются.
+func (s *Service) NormalizeArticleURLs(
+	ctx context.Context,
+	normalize func(conf config.SourceConfig, url string) string,
+	dryRun bool,
+) (NormalizeResult, error) {
+	var result NormalizeResult
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := s.queries.WithTx(tx)
+
+	rows, err := q.SelectArticleURLs(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	configs := make(map[string]config.SourceConfig)
+	groups := make(map[string][]queries.SelectArticleURLsRow)
+	order := make([]string, 0, len(rows))
+
+	for _, row := range rows {
+		conf, ok := configs[row.Config]
+		if !ok {
+			if conf, err = config.ParseSourceConfig([]byte(row.Config)); err != nil {
+				return result, fmt.Errorf("database: article %d: invalid source config: %v", row.ID, err)
+			}
+			configs[row.Config] = conf
+		}
+
+		url := normalize(conf, row.Url)
+		if _, ok := groups[url]; !ok {
+			order = append(order, url)
+		}
+		groups[url] = append(groups[url], row)
+	}
+
+	// owners статьи по текущим адресам: адрес, к которому приводится статья,
+	// может быть занят статьёй источника с другими правилами нормализации.
+	owners := make(map[string]queries.SelectArticleURLsRow, len(rows))
+	for _, row := range rows {
+		owners[row.Url] = row
+	}
+
+	move := func(row queries.SelectArticleURLsRow, url string, sent int64) error {
+		if err := q.UpdateArticleURL(ctx, queries.UpdateArticleURLParams{
+			Url:  url,
+			Sent: sent,
+			ID:   row.ID,
+		}); err != nil {
+			return fmt.Errorf("database: unable to update article %d: %v", row.ID, err)
+		}
+
+		if row.Url != url {
+			result.Updated++
+		}
+
+		delete(owners, row.Url)
+		row.Url, row.Sent = url, sent
+		owners[url] = row
+
+		return nil
+	}
+
+	remove := func(row queries.SelectArticleURLsRow) error {
+		if err := q.DeleteArticle(ctx, row.ID); err != nil {
+			return fmt.Errorf("database: unable to delete article %d: %v", row.ID, err)
+		}
+
+		delete(owners, row.Url)
+		result.Merged++
+
+		return nil
+	}
+
+	// pending статьи, адрес которых пока занят.
+	var pending []normalizedArticle
+
+	for _, url := range order {
+		group := groups[url]
+
+		// Строки отсортированы по id, первая - самая ранняя.
+		kept := group[0]
+		var sent int64
+		for _, row := range group {
+			if row.Sent > sent {
+				sent = row.Sent
+			}
+			if row.Sent > 0 && kept.Sent == 0 {
+				kept = row
+			}
+		}
+
+		for _, row := range group {
+			if row.ID == kept.ID {
+				continue
+			}
+			if err := remove(row); err != nil {
+				return result, err
+			}
+		}
+
+		if kept.Url == url && kept.Sent == sent {
+			continue
+		}
+
+		if owner, ok := owners[url]; ok && owner.ID != kept.ID {
+			pending = append(pending, normalizedArticle{row: kept, url: url, sent: sent})
+			continue
+		}
+
+		if err := move(kept, url, sent); err != nil {
+			return result, err
+		}
+	}
+
+	// Адрес освобождается, когда занявшая его статья приводится к своему.
+	for len(pending) > 0 {
+		blocked := pending[:0]
+
+		for _, article := range pending {
+			if _, ok := owners[article.url]; ok {
+				blocked = append(blocked, article)
+				continue
+			}
+
+			// Статья могла получить отметку отправки от удалённого повтора.
+			sent := article.sent
+			if current := owners[article.row.Url]; current.ID == article.row.ID && current.Sent > sent {
+				sent = current.Sent
+			}
+
+			if err := move(article.row, article.url, sent); err != nil {
+				return result, err
+			}
+		}
+
+		// Адрес остался занят: это та же статья, сохранённая другим
+		// источником. Остаётся статья с этим адресом, повтор удаляется.
+		if len(blocked) == len(pending) {
+			article := blocked[0]
+			blocked = blocked[1:]
+
+			owner := owners[article.url]
+
+			if err := remove(article.row); err != nil {
+				return result, err
+			}
+
+			if article.sent > owner.Sent {
+				if err := move(owner, owner.Url, article.sent); err != nil {
+					return result, err
+				}
+			}
+		}
+
+		pending = blocked
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	return result, tx.Commit()
+}
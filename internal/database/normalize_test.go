@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+type testArticle struct {
+	URL  string
+	Sent int64
+}
+
+func TestServiceNormalizeArticleURLs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		articles   []testArticle
+		normalized map[string]string
+		want       map[int64]testArticle
+		wantResult NormalizeResult
+	}{
+		{
+			name: "duplicates merged into sent article",
+			articles: []testArticle{
+				{URL: "https://example.com/post?utm_source=x"},
+				{URL: "https://example.com/post?utm_source=y", Sent: 100},
+			},
+			normalized: map[string]string{
+				"https://example.com/post?utm_source=x": "https://example.com/post",
+				"https://example.com/post?utm_source=y": "https://example.com/post",
+			},
+			want: map[int64]testArticle{
+				2: {URL: "https://example.com/post", Sent: 100},
+			},
+			wantResult: NormalizeResult{Updated: 1, Merged: 1},
+		},
+		{
+			name: "address freed by later article",
+			articles: []testArticle{
+				{URL: "http://example.com/post/"},
+				{URL: "https://example.com/post/", Sent: 100},
+			},
+			normalized: map[string]string{
+				"http://example.com/post/":  "https://example.com/post/",
+				"https://example.com/post/": "https://example.com/post",
+			},
+			want: map[int64]testArticle{
+				1: {URL: "https://example.com/post/"},
+				2: {URL: "https://example.com/post", Sent: 100},
+			},
+			wantResult: NormalizeResult{Updated: 2},
+		},
+		{
+			name: "address stays taken",
+			articles: []testArticle{
+				{URL: "https://example.com/a", Sent: 100},
+				{URL: "https://example.com/b"},
+			},
+			normalized: map[string]string{
+				"https://example.com/a": "https://example.com/b",
+				"https://example.com/b": "https://example.com/a",
+			},
+			want: map[int64]testArticle{
+				2: {URL: "https://example.com/a", Sent: 100},
+			},
+			wantResult: NormalizeResult{Updated: 1, Merged: 1},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			db, err := New(filepath.Join(t.TempDir(), "feedparser.db"))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = db.Close() })
+
+			service := NewService(db)
+			require.NoError(t, service.UpsertSource(ctx, "source", "https://example.com", "{}"))
+
+			for i, article := range tc.articles {
+				_, err := db.Exec(
+					`INSERT INTO articles (id, source_id, url, sent) VALUES (?, (SELECT id FROM sources), ?, ?)`,
+					i+1, article.URL, article.Sent,
+				)
+				require.NoError(t, err)
+			}
+
+			// act
+			result, err := service.NormalizeArticleURLs(ctx, func(_ config.SourceConfig, url string) string {
+				if normalized, ok := tc.normalized[url]; ok {
+					return normalized
+				}
+				return url
+			}, false)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.wantResult, result)
+
+			rows, err := db.Query(`SELECT id, url, sent FROM articles`)
+			require.NoError(t, err)
+			defer rows.Close()
+
+			got := make(map[int64]testArticle)
+			for rows.Next() {
+				var (
+					id      int64
+					article testArticle
+				)
+				require.NoError(t, rows.Scan(&id, &article.URL, &article.Sent))
+				got[id] = article
+			}
+			require.NoError(t, rows.Err())
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -20,6 +20,11 @@ UPDATE sources
 SET retries = retries + 1
 WHERE id = sqlc.arg(id);
 
+-- name: SetSourceBroken :exec
+UPDATE sources
+SET broken = sqlc.arg(broken)
+WHERE id = sqlc.arg(id);
+
 -- name: UpsertSource :exec
 INSERT INTO sources (name, url, config)
 VALUES (sqlc.arg(name),
@@ -29,15 +34,40 @@ ON CONFLICT (url)
     DO UPDATE
     SET config = excluded.config;
 
--- name: UpsertArticle :exec
-INSERT INTO articles (source_id, title, url, added)
+-- name: UpsertArticle :execrows
+INSERT INTO articles (source_id, title, url, added, published, author, summary, image, canonical_url)
+VALUES (sqlc.arg(source_id),
+        sqlc.arg(title),
+        sqlc.arg(url),
+        sqlc.arg(added),
+        sqlc.arg(published),
+        sqlc.arg(author),
+        sqlc.arg(summary),
+        sqlc.arg(image),
+        sqlc.arg(canonical_url))
+ON CONFLICT (url)
+    DO NOTHING;
+
+-- name: UpsertFilteredArticle :execrows
+INSERT INTO filtered_articles (source_id, title, url, reason, added)
 VALUES (sqlc.arg(source_id),
         sqlc.arg(title),
         sqlc.arg(url),
+        sqlc.arg(reason),
         sqlc.arg(added))
 ON CONFLICT (url)
     DO NOTHING;
 
+-- name: ArticleExists :one
+SELECT COUNT(*) > 0
+FROM articles
+WHERE url = sqlc.arg(url);
+
+-- name: FilteredArticleExists :one
+SELECT COUNT(*) > 0
+FROM filtered_articles
+WHERE url = sqlc.arg(url);
+
 -- name: LastTimestamp :one
 SELECT timestamp
 FROM timestamp
@@ -53,14 +83,82 @@ COMMIT;
 SELECT a.id,
        a.title,
        a.url,
+       a.published,
+       a.author,
+       a.summary,
+       a.image,
+       a.canonical_url,
        s.name as source_name,
        s.config as config
 FROM articles as a
 JOIN sources s on s.id = a.source_id
 WHERE sent = 0
+  AND (a.published = 0 OR a.published >= sqlc.arg(published_after))
+ORDER BY CASE WHEN a.published > 0 THEN a.published ELSE a.added END, a.id
 LIMIT 1;
 
 -- name: MarkArticleSent :exec
 UPDATE articles
 SET sent = 1
 WHERE id = sqlc.arg(id);
+
+-- name: SelectArticleURLs :many
+SELECT a.id,
+       a.url,
+       a.sent,
+       s.config as config
+FROM articles as a
+JOIN sources s on s.id = a.source_id
+ORDER BY a.id;
+
+-- name: UpdateArticleURL :exec
+UPDATE articles
+SET url  = sqlc.arg(url),
+    sent = sqlc.arg(sent)
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteArticle :exec
+DELETE
+FROM articles
+WHERE id = sqlc.arg(id);
+
+-- name: InsertCrawl :exec
+INSERT INTO crawls (source_id, started, finished, pages, matched, accepted, rejected, saved, reasons, error, run)
+VALUES (sqlc.arg(source_id),
+        sqlc.arg(started),
+        sqlc.arg(finished),
+        sqlc.arg(pages),
+        sqlc.arg(matched),
+        sqlc.arg(accepted),
+        sqlc.arg(rejected),
+        sqlc.arg(saved),
+        sqlc.arg(reasons),
+        sqlc.arg(error),
+        sqlc.arg(run));
+
+-- name: SelectRecentCrawls :many
+SELECT *
+FROM crawls
+WHERE source_id = sqlc.arg(source_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: SelectLastSnapshot :one
+SELECT *
+FROM page_snapshots
+WHERE source_id = sqlc.arg(source_id)
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: InsertSnapshot :exec
+INSERT INTO page_snapshots (source_id, hash, text, added, checked)
+VALUES (sqlc.arg(source_id),
+        sqlc.arg(hash),
+        sqlc.arg(text),
+        sqlc.arg(added),
+        sqlc.arg(added));
+
+-- name: UpdateSnapshotChecked :exec
+UPDATE page_snapshots
+SET checked = sqlc.arg(checked)
+WHERE id = sqlc.arg(id);
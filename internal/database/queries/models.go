@@ -5,12 +5,50 @@
 package queries
 
 type Article struct {
+	ID           int64
+	SourceID     int64
+	Title        string
+	Url          string
+	Sent         int64
+	Added        int64
+	Published    int64
+	Author       string
+	Summary      string
+	Image        string
+	CanonicalUrl string
+}
+
+type Crawl struct {
+	ID       int64
+	SourceID int64
+	Started  int64
+	Finished int64
+	Pages    int64
+	Matched  int64
+	Accepted int64
+	Rejected int64
+	Saved    int64
+	Reasons  string
+	Error    string
+	Run      int64
+}
+
+type FilteredArticle struct {
 	ID       int64
 	SourceID int64
 	Title    string
 	Url      string
-	Sent     int64
+	Reason   string
+	Added    int64
+}
+
+type PageSnapshot struct {
+	ID       int64
+	SourceID int64
+	Hash     string
+	Text     string
 	Added    int64
+	Checked  int64
 }
 
 type Source struct {
@@ -20,6 +58,7 @@ type Source struct {
 	Config      string
 	LastVisited int64
 	Retries     int64
+	Broken      int64
 }
 
 type Timestamp struct {
@@ -9,8 +9,45 @@ import (
 	"context"
 )
 
+const articleExists = `-- name: ArticleExists :one
+SELECT COUNT(*) > 0
+FROM articles
+WHERE url = ?1
+`
+
+func (q *Queries) ArticleExists(ctx context.Context, url string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, articleExists, url)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const deleteArticle = `-- name: DeleteArticle :exec
+DELETE
+FROM articles
+WHERE id = ?1
+`
+
+func (q *Queries) DeleteArticle(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteArticle, id)
+	return err
+}
+
+const filteredArticleExists = `-- name: FilteredArticleExists :one
+SELECT COUNT(*) > 0
+FROM filtered_articles
+WHERE url = ?1
+`
+
+func (q *Queries) FilteredArticleExists(ctx context.Context, url string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, filteredArticleExists, url)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const fetchOne = `-- name: FetchOne :one
-SELECT id, url, name, config, last_visited, retries
+SELECT id, url, name, config, last_visited, retries, broken
 FROM sources
 WHERE last_visited < ?1
   AND retries < ?2
@@ -33,10 +70,83 @@ func (q *Queries) FetchOne(ctx context.Context, arg FetchOneParams) (Source, err
 		&i.Config,
 		&i.LastVisited,
 		&i.Retries,
+		&i.Broken,
 	)
 	return i, err
 }
 
+const insertCrawl = `-- name: InsertCrawl :exec
+INSERT INTO crawls (source_id, started, finished, pages, matched, accepted, rejected, saved, reasons, error, run)
+VALUES (?1,
+        ?2,
+        ?3,
+        ?4,
+        ?5,
+        ?6,
+        ?7,
+        ?8,
+        ?9,
+        ?10,
+        ?11)
+`
+
+type InsertCrawlParams struct {
+	SourceID int64
+	Started  int64
+	Finished int64
+	Pages    int64
+	Matched  int64
+	Accepted int64
+	Rejected int64
+	Saved    int64
+	Reasons  string
+	Error    string
+	Run      int64
+}
+
+func (q *Queries) InsertCrawl(ctx context.Context, arg InsertCrawlParams) error {
+	_, err := q.db.ExecContext(ctx, insertCrawl,
+		arg.SourceID,
+		arg.Started,
+		arg.Finished,
+		arg.Pages,
+		arg.Matched,
+		arg.Accepted,
+		arg.Rejected,
+		arg.Saved,
+		arg.Reasons,
+		arg.Error,
+		arg.Run,
+	)
+	return err
+}
+
+const insertSnapshot = `-- name: InsertSnapshot :exec
+INSERT INTO page_snapshots (source_id, hash, text, added, checked)
+VALUES (?1,
+        ?2,
+        ?3,
+        ?4,
+        ?4)
+`
+
+type InsertSnapshotParams struct {
+	SourceID int64
+	Hash     string
+	Text     string
+	Added    int64
+}
+
+func (q *Queries) InsertSnapshot(ctx context.Context, arg InsertSnapshotParams) error {
+	_, err := q.db.ExecContext(ctx, insertSnapshot,
+		arg.SourceID,
+		arg.Hash,
+		arg.Text,
+		arg.Added,
+	)
+	return err
+}
+
 const lastTimestamp = `-- name: LastTimestamp :one
 SELECT timestamp
 FROM timestamp
@@ -72,35 +182,168 @@ func (q *Queries) ResetRetries(ctx context.Context) error {
 	return err
 }
 
+const selectArticleURLs = `-- name: SelectArticleURLs :many
+SELECT a.id,
+       a.url,
+       a.sent,
+       s.config as config
+FROM articles as a
+JOIN sources s on s.id = a.source_id
+ORDER BY a.id
+`
+
+type SelectArticleURLsRow struct {
+	ID     int64
+	Url    string
+	Sent   int64
+	Config string
+}
+
+func (q *Queries) SelectArticleURLs(ctx context.Context) ([]SelectArticleURLsRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectArticleURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectArticleURLsRow
+	for rows.Next() {
+		var i SelectArticleURLsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Sent,
+			&i.Config,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectLastSnapshot = `-- name: SelectLastSnapshot :one
+SELECT id, source_id, hash, text, added, checked
+FROM page_snapshots
+WHERE source_id = ?1
+ORDER BY id DESC
+LIMIT 1
+`
+
+func (q *Queries) SelectLastSnapshot(ctx context.Context, sourceID int64) (PageSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, selectLastSnapshot, sourceID)
+	var i PageSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.SourceID,
+		&i.Hash,
+		&i.Text,
+		&i.Added,
+		&i.Checked,
+	)
+	return i, err
+}
+
+const selectRecentCrawls = `-- name: SelectRecentCrawls :many
+SELECT id, source_id, started, finished, pages, matched, accepted, rejected, saved, reasons, error, run
+FROM crawls
+WHERE source_id = ?1
+ORDER BY id DESC
+LIMIT ?2
+`
+
+type SelectRecentCrawlsParams struct {
+	SourceID int64
+	Limit    int64
+}
+
+func (q *Queries) SelectRecentCrawls(ctx context.Context, arg SelectRecentCrawlsParams) ([]Crawl, error) {
+	rows, err := q.db.QueryContext(ctx, selectRecentCrawls, arg.SourceID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Crawl
+	for rows.Next() {
+		var i Crawl
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceID,
+			&i.Started,
+			&i.Finished,
+			&i.Pages,
+			&i.Matched,
+			&i.Accepted,
+			&i.Rejected,
+			&i.Saved,
+			&i.Reasons,
+			&i.Error,
+			&i.Run,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectUnsent = `-- name: SelectUnsent :one
 COMMIT;
 
 SELECT a.id,
        a.title,
        a.url,
+       a.published,
+       a.author,
+       a.summary,
+       a.image,
+       a.canonical_url,
        s.name as source_name,
        s.config as config
 FROM articles as a
 JOIN sources s on s.id = a.source_id
 WHERE sent = 0
+  AND (a.published = 0 OR a.published >= ?1)
+ORDER BY CASE WHEN a.published > 0 THEN a.published ELSE a.added END, a.id
 LIMIT 1
 `
 
 type SelectUnsentRow struct {
-	ID         int64
-	Title      string
-	Url        string
-	SourceName string
-	Config     string
+	ID           int64
+	Title        string
+	Url          string
+	Published    int64
+	Author       string
+	Summary      string
+	Image        string
+	CanonicalUrl string
+	SourceName   string
+	Config       string
 }
 
-func (q *Queries) SelectUnsent(ctx context.Context) (SelectUnsentRow, error) {
-	row := q.db.QueryRowContext(ctx, selectUnsent)
+func (q *Queries) SelectUnsent(ctx context.Context, publishedAfter int64) (SelectUnsentRow, error) {
+	row := q.db.QueryRowContext(ctx, selectUnsent, publishedAfter)
 	var i SelectUnsentRow
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
 		&i.Url,
+		&i.Published,
+		&i.Author,
+		&i.Summary,
+		&i.Image,
+		&i.CanonicalUrl,
 		&i.SourceName,
 		&i.Config,
 	)
@@ -117,6 +360,40 @@ func (q *Queries) SetLastTimestamp(ctx context.Context, timestamp int64) error {
 	return err
 }
 
+const setSourceBroken = `-- name: SetSourceBroken :exec
+UPDATE sources
+SET broken = ?1
+WHERE id = ?2
+`
+
+type SetSourceBrokenParams struct {
+	Broken int64
+	ID     int64
+}
+
+func (q *Queries) SetSourceBroken(ctx context.Context, arg SetSourceBrokenParams) error {
+	_, err := q.db.ExecContext(ctx, setSourceBroken, arg.Broken, arg.ID)
+	return err
+}
+
+const updateArticleURL = `-- name: UpdateArticleURL :exec
+UPDATE articles
+SET url  = ?1,
+    sent = ?2
+WHERE id = ?3
+`
+
+type UpdateArticleURLParams struct {
+	Url  string
+	Sent int64
+	ID   int64
+}
+
+func (q *Queries) UpdateArticleURL(ctx context.Context, arg UpdateArticleURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateArticleURL, arg.Url, arg.Sent, arg.ID)
+	return err
+}
+
 const updateLastVisited = `-- name: UpdateLastVisited :exec
 UPDATE sources
 SET last_visited = ?1
@@ -144,31 +421,98 @@ func (q *Queries) UpdateRetries(ctx context.Context, id int64) error {
 	return err
 }
 
-const upsertArticle = `-- name: UpsertArticle :exec
-INSERT INTO articles (source_id, title, url, added)
+const updateSnapshotChecked = `-- name: UpdateSnapshotChecked :exec
+UPDATE page_snapshots
+SET checked = ?1
+WHERE id = ?2
+`
+
+type UpdateSnapshotCheckedParams struct {
+	Checked int64
+	ID      int64
+}
+
+func (q *Queries) UpdateSnapshotChecked(ctx context.Context, arg UpdateSnapshotCheckedParams) error {
+	_, err := q.db.ExecContext(ctx, updateSnapshotChecked, arg.Checked, arg.ID)
+	return err
+}
+
+const upsertArticle = `-- name: UpsertArticle :execrows
+INSERT INTO articles (source_id, title, url, added, published, author, summary, image, canonical_url)
 VALUES (?1,
         ?2,
         ?3,
-        ?4)
+        ?4,
+        ?5,
+        ?6,
+        ?7,
+        ?8,
+        ?9)
 ON CONFLICT (url)
     DO NOTHING
 `
 
 type UpsertArticleParams struct {
+	SourceID     int64
+	Title        string
+	Url          string
+	Added        int64
+	Published    int64
+	Author       string
+	Summary      string
+	Image        string
+	CanonicalUrl string
+}
+
+func (q *Queries) UpsertArticle(ctx context.Context, arg UpsertArticleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertArticle,
+		arg.SourceID,
+		arg.Title,
+		arg.Url,
+		arg.Added,
+		arg.Published,
+		arg.Author,
+		arg.Summary,
+		arg.Image,
+		arg.CanonicalUrl,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertFilteredArticle = `-- name: UpsertFilteredArticle :execrows
+INSERT INTO filtered_articles (source_id, title, url, reason, added)
+VALUES (?1,
+        ?2,
+        ?3,
+        ?4,
+        ?5)
+ON CONFLICT (url)
+    DO NOTHING
+`
+
+type UpsertFilteredArticleParams struct {
 	SourceID int64
 	Title    string
 	Url      string
+	Reason   string
 	Added    int64
 }
 
-func (q *Queries) UpsertArticle(ctx context.Context, arg UpsertArticleParams) error {
-	_, err := q.db.ExecContext(ctx, upsertArticle,
+func (q *Queries) UpsertFilteredArticle(ctx context.Context, arg UpsertFilteredArticleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertFilteredArticle,
 		arg.SourceID,
 		arg.Title,
 		arg.Url,
+		arg.Reason,
 		arg.Added,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const upsertSource = `-- name: UpsertSource :exec
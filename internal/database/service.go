@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/denisdubovitskiy/feedparser/internal/config"
 	"github.com/denisdubovitskiy/feedparser/internal/database/queries"
+	"github.com/denisdubovitskiy/feedparser/internal/unix"
 )
 
 type DBTX interface {
@@ -39,6 +41,9 @@ type Source struct {
 	Config      config.SourceConfig
 	LastVisited int64
 	Retries     int64
+	// Broken источник помечен как сломанный: его селекторы перестали
+	// находить карточки статей.
+	Broken bool
 }
 
 func (s Source) String() string {
@@ -47,18 +52,124 @@ func (s Source) String() string {
 
 type SaveArticleParams = queries.UpsertArticleParams
 
-func (s *Service) SaveArticle(ctx context.Context, params SaveArticleParams) error {
+// SaveArticle сохраняет статью и сообщает, была ли она новой.
+func (s *Service) SaveArticle(ctx context.Context, params SaveArticleParams) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return s.queries.UpsertArticle(ctx, params)
+	rows, err := s.queries.UpsertArticle(ctx, params)
+	return rows > 0, err
+}
+
+type SaveFilteredArticleParams = queries.UpsertFilteredArticleParams
+
+// SaveFilteredArticle сохраняет статью, отброшенную фильтрами источника,
+// вместе с причиной, чтобы отбор можно было проверить, и сообщает,
+// была ли она новой.
+func (s *Service) SaveFilteredArticle(ctx context.Context, params SaveFilteredArticleParams) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows, err := s.queries.UpsertFilteredArticle(ctx, params)
+	return rows > 0, err
+}
+
+type SaveCrawlParams = queries.InsertCrawlParams
+
+// SaveCrawl сохраняет итог обхода источника: сколько карточек найдено,
+// сколько статей принято, отклонено и сохранено. Повторные попытки
+// одного запуска раннера сохраняются с одинаковым Run.
+func (s *Service) SaveCrawl(ctx context.Context, params SaveCrawlParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.InsertCrawl(ctx, params)
+}
+
+type Crawl = queries.Crawl
+
+// RecentCrawls возвращает последние обходы источника, начиная с самого нового.
+func (s *Service) RecentCrawls(ctx context.Context, sourceID, limit int64) ([]Crawl, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.SelectRecentCrawls(ctx, queries.SelectRecentCrawlsParams{
+		SourceID: sourceID,
+		Limit:    limit,
+	})
+}
+
+// SetSourceBroken помечает источник как сломанный или снимает пометку.
+func (s *Service) SetSourceBroken(ctx context.Context, id int64, broken bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var value int64
+	if broken {
+		value = 1
+	}
+
+	return s.queries.SetSourceBroken(ctx, queries.SetSourceBrokenParams{
+		Broken: value,
+		ID:     id,
+	})
+}
+
+type Snapshot = queries.PageSnapshot
+
+// LastSnapshot возвращает последний сохранённый снимок отслеживаемой страницы
+// или sql.ErrNoRows, если страница ещё не проверялась.
+func (s *Service) LastSnapshot(ctx context.Context, sourceID int64) (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.SelectLastSnapshot(ctx, sourceID)
+}
+
+// SaveSnapshot сохраняет новый снимок страницы. Снимки сохраняются только
+// при изменении текста, неизменность отмечается через TouchSnapshot.
+func (s *Service) SaveSnapshot(ctx context.Context, sourceID int64, hash, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.InsertSnapshot(ctx, queries.InsertSnapshotParams{
+		SourceID: sourceID,
+		Hash:     hash,
+		Text:     text,
+		Added:    unix.TimeNow(),
+	})
+}
+
+// TouchSnapshot отмечает, что страница проверена и не изменилась.
+func (s *Service) TouchSnapshot(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.UpdateSnapshotChecked(ctx, queries.UpdateSnapshotCheckedParams{
+		Checked: unix.TimeNow(),
+		ID:      id,
+	})
+}
+
+func (s *Service) ArticleExists(ctx context.Context, url string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.ArticleExists(ctx, url)
+}
+
+// FilteredArticleExists сообщает, что статья уже была отброшена фильтрами.
+func (s *Service) FilteredArticleExists(ctx context.Context, url string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.FilteredArticleExists(ctx, url)
 }
 
 type Article struct {
-	Title    string
-	URL      string
-	Source   string
-	Channels []string
-	Tags     []string
+	Title string
+	URL   string
+	// Published дата публикации, нулевая, если неизвестна.
+	Published time.Time
+	Author    string
+	Summary   string
+	ImageURL  string
+	// CanonicalURL адрес статьи, указанный самой страницей.
+	CanonicalURL string
+	Source       string
+	Channels     []string
+	Tags         []string
 }
 
 func (a Article) String() string {
@@ -70,7 +181,11 @@ func formatArray(channels []string) string {
 	return fmt.Sprintf("[%s]", ch)
 }
 
-func (s *Service) SelectUnsent(ctx context.Context, f func(a Article) error) error {
+// SelectUnsent передаёт f самую раннюю по дате публикации неотправленную
+// статью и помечает её отправленной. Статьи без даты публикации упорядочиваются
+// по времени обнаружения. Статьи, опубликованные до publishedAfter, пропускаются;
+// нулевое значение отключает отбор.
+func (s *Service) SelectUnsent(ctx context.Context, publishedAfter time.Time, f func(a Article) error) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -81,7 +196,7 @@ func (s *Service) SelectUnsent(ctx context.Context, f func(a Article) error) err
 
 	q := s.queries.WithTx(tx)
 
-	article, err := q.SelectUnsent(ctx)
+	article, err := q.SelectUnsent(ctx, unix.FromTime(publishedAfter))
 	if err != nil {
 		_ = tx.Rollback()
 		return err
@@ -93,11 +208,16 @@ func (s *Service) SelectUnsent(ctx context.Context, f func(a Article) error) err
 	}
 
 	fnErr := f(Article{
-		Title:    article.Title,
-		URL:      article.Url,
-		Source:   article.SourceName,
-		Channels: conf.Channels,
-		Tags:     conf.Tags,
+		Title:        article.Title,
+		URL:          article.Url,
+		Published:    unix.ToTime(article.Published),
+		Author:       article.Author,
+		Summary:      article.Summary,
+		ImageURL:     article.Image,
+		CanonicalURL: article.CanonicalUrl,
+		Source:       article.SourceName,
+		Channels:     conf.Channels,
+		Tags:         conf.Tags,
 	})
 	if fnErr != nil {
 		_ = tx.Rollback()
@@ -117,6 +237,33 @@ func (s *Service) SelectUnsent(ctx context.Context, f func(a Article) error) err
 func (s *Service) FetchOne(ctx context.Context, unixTimeUntil, maxRetries int64) (*Source, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.fetchOne(ctx, unixTimeUntil, maxRetries)
+}
+
+// ClaimOne выбирает источник, как FetchOne, и сразу отмечает его посещённым
+// в claimedAt, чтобы параллельные обработчики не получили его повторно.
+// После сбоя обработки отметку возвращают через UpdateLastVisited.
+func (s *Service) ClaimOne(ctx context.Context, unixTimeUntil, maxRetries, claimedAt int64) (*Source, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, err := s.fetchOne(ctx, unixTimeUntil, maxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.queries.UpdateLastVisited(ctx, queries.UpdateLastVisitedParams{
+		ID:          source.ID,
+		LastVisited: claimedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+func (s *Service) fetchOne(ctx context.Context, unixTimeUntil, maxRetries int64) (*Source, error) {
 	source, err := s.queries.FetchOne(ctx, queries.FetchOneParams{
 		UnixTimeUntil: unixTimeUntil,
 		MaxRetries:    maxRetries,
@@ -137,6 +284,7 @@ func (s *Service) FetchOne(ctx context.Context, unixTimeUntil, maxRetries int64)
 		Config:      conf,
 		LastVisited: source.LastVisited,
 		Retries:     source.Retries,
+		Broken:      source.Broken > 0,
 	}, err
 }
 
@@ -0,0 +1,77 @@
+package fetcher
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// gzipMagic начало данных, сжатых gzip: карты сайта *.xml.gz.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// binaryContentTypes типы ответов, которые нельзя перекодировать как текст.
+var binaryContentTypes = map[string]struct{}{
+	"application/gzip":         {},
+	"application/x-gzip":       {},
+	"application/octet-stream": {},
+	"application/zip":          {},
+}
+
+// regexpXMLEncoding объявление кодировки лент: <?xml version="1.0" encoding="windows-1251"?>.
+var regexpXMLEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*\sencoding\s*=\s*["']([\w.:-]+)["']`)
+
+// decodeBody приводит тело ответа к UTF-8. Кодировка определяется по BOM,
+// charset заголовка Content-Type, объявлению <?xml encoding?> и <meta charset>.
+// Корректный UTF-8 не перекодируется: сайты нередко объявляют кодировку,
+// в которой страница уже не отдаётся. Двоичные ответы возвращаются как есть.
+func decodeBody(body []byte, contentType string) string {
+	if bytes.HasPrefix(body, utf8BOM) {
+		return string(body[len(utf8BOM):])
+	}
+
+	if utf8.Valid(body) || isBinary(body, contentType) {
+		return string(body)
+	}
+
+	enc, name, certain := charset.DetermineEncoding(body, contentType)
+
+	if !certain {
+		if m := regexpXMLEncoding.FindSubmatch(body); m != nil {
+			if xmlEnc, xmlName := charset.Lookup(string(m[1])); xmlEnc != nil {
+				enc, name = xmlEnc, xmlName
+			}
+		}
+	}
+
+	if name == "utf-8" {
+		return string(body)
+	}
+
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(decoded)
+}
+
+// isBinary сообщает, что тело ответа не является текстом: сжато gzip
+// или отдано с двоичным типом содержимого.
+func isBinary(body []byte, contentType string) bool {
+	if bytes.HasPrefix(body, gzipMagic) {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	_, ok := binaryContentTypes[mediaType]
+	return ok
+}
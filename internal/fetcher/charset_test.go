@@ -0,0 +1,143 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const listingTitle = "Новости: обновление Go"
+
+func encode(t *testing.T, enc encoding.Encoding, s string) []byte {
+	t.Helper()
+
+	b, err := enc.NewEncoder().Bytes([]byte(s))
+	require.NoError(t, err)
+
+	return b
+}
+
+func listing(meta string) string {
+	return `<html><head>` + meta + `<title>Блог</title></head><body>
+<article><h2>` + listingTitle + `</h2><a href="/news/1">Читать</a></article>
+</body></html>`
+}
+
+func TestDecodeBody(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		body        func(t *testing.T) []byte
+		contentType string
+		want        string
+	}{
+		{
+			name:        "utf-8",
+			body:        func(t *testing.T) []byte { return []byte(listing("")) },
+			contentType: "text/html",
+			want:        listing(""),
+		},
+		{
+			name:        "utf-8 with bom",
+			body:        func(t *testing.T) []byte { return append([]byte("\xef\xbb\xbf"), listing("")...) },
+			contentType: "text/html",
+			want:        listing(""),
+		},
+		{
+			name:        "content type charset",
+			body:        func(t *testing.T) []byte { return encode(t, charmap.Windows1251, listing("")) },
+			contentType: "text/html; charset=windows-1251",
+			want:        listing(""),
+		},
+		{
+			name: "meta charset",
+			body: func(t *testing.T) []byte {
+				return encode(t, charmap.Windows1251, listing(`<meta charset="windows-1251">`))
+			},
+			contentType: "text/html",
+			want:        listing(`<meta charset="windows-1251">`),
+		},
+		{
+			name: "meta http-equiv koi8-r",
+			body: func(t *testing.T) []byte {
+				return encode(t, charmap.KOI8R, listing(`<meta http-equiv="Content-Type" content="text/html; charset=koi8-r">`))
+			},
+			want: listing(`<meta http-equiv="Content-Type" content="text/html; charset=koi8-r">`),
+		},
+		{
+			name: "content type wins over meta",
+			body: func(t *testing.T) []byte {
+				return encode(t, charmap.KOI8R, listing(`<meta charset="windows-1251">`))
+			},
+			contentType: "text/html; charset=KOI8-R",
+			want:        listing(`<meta charset="windows-1251">`),
+		},
+		{
+			name: "xml declaration",
+			body: func(t *testing.T) []byte {
+				return encode(t, charmap.Windows1251, `<?xml version="1.0" encoding="windows-1251"?><rss><channel><item><title>`+listingTitle+`</title></item></channel></rss>`)
+			},
+			contentType: "application/rss+xml",
+			want:        `<?xml version="1.0" encoding="windows-1251"?><rss><channel><item><title>` + listingTitle + `</title></item></channel></rss>`,
+		},
+		{
+			name:        "utf-8 with wrong declaration",
+			body:        func(t *testing.T) []byte { return []byte(listing(`<meta charset="windows-1251">`)) },
+			contentType: "text/html; charset=windows-1251",
+			want:        listing(`<meta charset="windows-1251">`),
+		},
+		{
+			name:        "gzip without content type",
+			body:        func(t *testing.T) []byte { return []byte("\x1f\x8b\x08\x00\x8b\xe2") },
+			contentType: "",
+			want:        "\x1f\x8b\x08\x00\x8b\xe2",
+		},
+		{
+			name:        "octet stream",
+			body:        func(t *testing.T) []byte { return []byte("PK\x03\x04\x8b\xe2") },
+			contentType: "application/octet-stream",
+			want:        "PK\x03\x04\x8b\xe2",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got := decodeBody(tc.body(t), tc.contentType)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestHTTPFetcherFetchCharset(t *testing.T) {
+	t.Parallel()
+
+	body := encode(t, charmap.Windows1251, listing(""))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher(server.Client())
+
+	// act
+	got, err := fetcher.Fetch(context.Background(), server.URL)
+
+	// assert
+	require.NoError(t, err)
+	require.Contains(t, got, listingTitle)
+}
@@ -0,0 +1,10 @@
+package fetcher
+
+import "context"
+
+// Fetcher загружает страницу и возвращает её HTML в UTF-8.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (string, error)
+}
+
+var _ Fetcher = (*HTTPFetcher)(nil)
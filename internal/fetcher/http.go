@@ -0,0 +1,50 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const userAgent = "Mozilla/5.0 (compatible; feedparser/1.0)"
+
+// maxBodySize ограничивает размер ответа, чтобы не держать в памяти
+// случайно попавшие в источник большие файлы.
+const maxBodySize = 10 << 20
+
+type HTTPFetcher struct {
+	client *http.Client
+}
+
+func NewHTTPFetcher(client *http.Client) *HTTPFetcher {
+	return &HTTPFetcher{client: client}
+}
+
+// Fetch загружает страницу без браузера и возвращает тело ответа,
+// приведённое к UTF-8.
+func (f *HTTPFetcher) Fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("fetcher: unable to create a request for %s: %v", url, err)
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetcher: unable to fetch %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetcher: unexpected status code %d for %s", resp.StatusCode, url)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return "", fmt.Errorf("fetcher: unable to read a response from %s: %v", url, err)
+	}
+
+	return decodeBody(body, resp.Header.Get("Content-Type")), nil
+}
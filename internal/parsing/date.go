@@ -0,0 +1,454 @@
+package parsing
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+	"2006.01.02",
+	"02.01.2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// parseDate возвращает первую успешно разобранную дату в UTC
+// или нулевое время, если ни одну из дат разобрать не удалось.
+func parseDate(values ...string) time.Time {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 {
+			continue
+		}
+
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.UTC()
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// dateLocale названия месяцев и слова относительных дат одного языка.
+type dateLocale struct {
+	// months названия месяцев в разных падежах и их сокращения, с января.
+	months [12][]string
+	// noise слова, не влияющие на дату: "at", "опубликовано".
+	noise []string
+	// yearWords слова после года: "2023 г.", "2023 года". Отбрасываются
+	// только после года, иначе "год назад" потеряет единицу.
+	yearWords []string
+	// weekdays названия дней недели, которые отбрасываются.
+	weekdays  []string
+	justNow   []string
+	today     []string
+	yesterday []string
+	// ago слово после количества: "3 days ago", "3 дня назад".
+	ago string
+	// units единицы относительных дат по префиксам слов.
+	units []relativeUnit
+}
+
+// relativeUnit единица относительной даты: "дн" - день, "hour" - час.
+type relativeUnit struct {
+	prefixes []string
+	years    int
+	months   int
+	days     int
+	duration time.Duration
+}
+
+var dateLocales = map[string]dateLocale{
+	"en": {
+		months: [12][]string{
+			{"january", "jan"},
+			{"february", "feb"},
+			{"march", "mar"},
+			{"april", "apr"},
+			{"may"},
+			{"june", "jun"},
+			{"july", "jul"},
+			{"august", "aug"},
+			{"september", "sep", "sept"},
+			{"october", "oct"},
+			{"november", "nov"},
+			{"december", "dec"},
+		},
+		noise:     []string{"at", "on", "published", "posted", "updated"},
+		weekdays:  []string{"monday", "mon", "tuesday", "tue", "wednesday", "wed", "thursday", "thu", "friday", "fri", "saturday", "sat", "sunday", "sun"},
+		justNow:   []string{"just now", "now"},
+		today:     []string{"today"},
+		yesterday: []string{"yesterday"},
+		ago:       "ago",
+		units: []relativeUnit{
+			{prefixes: []string{"sec", "second"}, duration: time.Second},
+			{prefixes: []string{"min", "minute"}, duration: time.Minute},
+			{prefixes: []string{"hour", "hr"}, duration: time.Hour},
+			{prefixes: []string{"day"}, days: 1},
+			{prefixes: []string{"week", "wk"}, days: 7},
+			{prefixes: []string{"month", "mo"}, months: 1},
+			{prefixes: []string{"year", "yr"}, years: 1},
+		},
+	},
+	"ru": {
+		months: [12][]string{
+			{"январь", "января", "янв"},
+			{"февраль", "февраля", "фев", "февр"},
+			{"март", "марта", "мар"},
+			{"апрель", "апреля", "апр"},
+			{"май", "мая"},
+			{"июнь", "июня", "июн"},
+			{"июль", "июля", "июл"},
+			{"август", "августа", "авг"},
+			{"сентябрь", "сентября", "сен", "сент"},
+			{"октябрь", "октября", "окт"},
+			{"ноябрь", "ноября", "ноя", "нояб"},
+			{"декабрь", "декабря", "дек"},
+		},
+		noise:     []string{"в", "опубликовано", "обновлено"},
+		yearWords: []string{"г", "год", "года"},
+		weekdays:  []string{"понедельник", "пн", "вторник", "вт", "среда", "ср", "четверг", "чт", "пятница", "пт", "суббота", "сб", "воскресенье", "вс"},
+		justNow:   []string{"только что", "сейчас"},
+		today:     []string{"сегодня"},
+		yesterday: []string{"вчера"},
+		ago:       "назад",
+		units: []relativeUnit{
+			{prefixes: []string{"сек"}, duration: time.Second},
+			{prefixes: []string{"мин"}, duration: time.Minute},
+			{prefixes: []string{"час"}, duration: time.Hour},
+			{prefixes: []string{"дн", "день", "сутк"}, days: 1},
+			{prefixes: []string{"недел"}, days: 7},
+			{prefixes: []string{"месяц"}, months: 1},
+			{prefixes: []string{"год", "лет"}, years: 1},
+		},
+	},
+}
+
+// dateLocalesFor возвращает языки для разбора дат: указанный
+// или все поддерживаемые, если язык не задан.
+func dateLocalesFor(locale string) ([]dateLocale, error) {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+
+	if len(locale) == 0 {
+		names := make([]string, 0, len(dateLocales))
+		for name := range dateLocales {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		locales := make([]dateLocale, 0, len(names))
+		for _, name := range names {
+			locales = append(locales, dateLocales[name])
+		}
+		return locales, nil
+	}
+
+	l, ok := dateLocales[locale]
+	if !ok {
+		return nil, fmt.Errorf("unknown date locale %q", locale)
+	}
+
+	return []dateLocale{l}, nil
+}
+
+// humanDateLayouts форматы дат после нормализации: запятые убраны,
+// месяцы приведены к "Jan", AM/PM - к верхнему регистру.
+var humanDateLayouts = []string{
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 2006 3:04 PM",
+	"2 Jan 2006",
+	"Jan 2 2006 15:04",
+	"Jan 2 2006 3:04 PM",
+	"Jan 2 2006",
+	"15:04 2 Jan 2006",
+	"02.01.2006 15:04",
+	"15:04 02.01.2006",
+	"2.1.2006",
+	"Jan 2006",
+}
+
+// yearlessDateLayouts форматы дат текущего года.
+var yearlessDateLayouts = []string{
+	"2 Jan 15:04",
+	"2 Jan",
+	"Jan 2 15:04",
+	"Jan 2 3:04 PM",
+	"Jan 2",
+	"15:04 2 Jan",
+}
+
+var clockLayouts = []string{"15:04", "15:04:05", "3:04 PM", "3 PM"}
+
+var regexpOrdinal = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)$`)
+
+// regexpYearSuffix год с приклеенным сокращением: "2023г.".
+var regexpYearSuffix = regexp.MustCompile(`^(\d{4})г$`)
+
+var regexpYear = regexp.MustCompile(`^\d{4}$`)
+
+// dateParser разбирает даты публикации из карточек списка: формат
+// источника, распространённые форматы на разных языках и относительные
+// даты вида "3 дня назад". Даты без часового пояса считаются UTC.
+type dateParser struct {
+	layout  string
+	locales []dateLocale
+	now     time.Time
+}
+
+func newDateParser(source Source, now time.Time) dateParser {
+	locales, err := dateLocalesFor(source.DateLocale)
+	if err != nil {
+		// Неизвестный язык отклоняется Validate, здесь пробуем все.
+		locales, _ = dateLocalesFor("")
+	}
+
+	return dateParser{
+		layout:  strings.TrimSpace(source.DateFormat),
+		locales: locales,
+		now:     now.UTC(),
+	}
+}
+
+// parse возвращает дату в UTC или нулевое время, если разобрать её не удалось.
+func (d dateParser) parse(value string) time.Time {
+	value = formatTitle(value)
+	if len(value) == 0 {
+		return time.Time{}
+	}
+
+	normalized := d.normalize(value)
+
+	if len(d.layout) > 0 {
+		for _, v := range []string{value, normalized} {
+			if t, err := time.Parse(d.layout, v); err == nil {
+				return d.withYear(t)
+			}
+		}
+	}
+
+	if t := parseDate(value); !t.IsZero() {
+		return t
+	}
+
+	if t, ok := d.relative(normalized); ok {
+		return t
+	}
+
+	for _, layout := range humanDateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return t.UTC()
+		}
+	}
+
+	for _, layout := range yearlessDateLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return d.withYear(t)
+		}
+	}
+
+	return time.Time{}
+}
+
+// normalize приводит дату к виду, понятному time.Parse: месяцы
+// переводятся в "Jan", дни недели и служебные слова отбрасываются.
+func (d dateParser) normalize(value string) string {
+	value = strings.ReplaceAll(value, ",", " ")
+
+	fields := strings.Fields(value)
+	tokens := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		token := strings.TrimSuffix(strings.ToLower(field), ".")
+
+		if m := regexpYearSuffix.FindStringSubmatch(token); m != nil {
+			token = m[1]
+		}
+
+		if m := regexpOrdinal.FindStringSubmatch(token); m != nil {
+			token = m[1]
+		}
+
+		switch token {
+		case "am", "pm":
+			tokens = append(tokens, strings.ToUpper(token))
+			continue
+		}
+
+		if month, ok := d.month(token); ok {
+			tokens = append(tokens, month.String()[:3])
+			continue
+		}
+
+		if d.skip(token) {
+			continue
+		}
+
+		if len(tokens) > 0 && regexpYear.MatchString(tokens[len(tokens)-1]) && d.yearWord(token) {
+			continue
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+func (d dateParser) month(token string) (time.Month, bool) {
+	for _, l := range d.locales {
+		for i, names := range l.months {
+			for _, name := range names {
+				if token == name {
+					return time.Month(i + 1), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func (d dateParser) skip(token string) bool {
+	for _, l := range d.locales {
+		for _, words := range [][]string{l.noise, l.weekdays} {
+			for _, word := range words {
+				if token == word {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (d dateParser) yearWord(token string) bool {
+	for _, l := range d.locales {
+		for _, word := range l.yearWords {
+			if token == word {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// relative разбирает относительные даты: "только что", "вчера в 14:30",
+// "3 days ago", "час назад".
+func (d dateParser) relative(value string) (time.Time, bool) {
+	for _, l := range d.locales {
+		for _, word := range l.justNow {
+			if value == word {
+				return d.now, true
+			}
+		}
+
+		for days, words := range [][]string{l.today, l.yesterday} {
+			for _, word := range words {
+				rest, ok := strings.CutPrefix(value, word)
+				if !ok {
+					continue
+				}
+
+				return d.day(days, strings.TrimSpace(rest))
+			}
+		}
+
+		rest, ok := strings.CutSuffix(value, " "+l.ago)
+		if !ok {
+			continue
+		}
+
+		if t, ok := d.ago(l, strings.Fields(rest)); ok {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// day возвращает начало дня days дней назад или указанное время этого дня.
+func (d dateParser) day(days int, clock string) (time.Time, bool) {
+	year, month, day := d.now.AddDate(0, 0, -days).Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	if len(clock) == 0 {
+		return date, true
+	}
+
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, clock); err == nil {
+			return date.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// ago разбирает количество и единицу: ["3", "дня"], ["an", "hour"], ["час"].
+func (d dateParser) ago(l dateLocale, fields []string) (time.Time, bool) {
+	count := 1
+
+	switch len(fields) {
+	case 1:
+	case 2:
+		switch fields[0] {
+		case "a", "an", "one":
+		default:
+			n, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return time.Time{}, false
+			}
+			count = n
+		}
+		fields = fields[1:]
+	default:
+		return time.Time{}, false
+	}
+
+	for _, unit := range l.units {
+		for _, prefix := range unit.prefixes {
+			if !strings.HasPrefix(fields[0], prefix) {
+				continue
+			}
+
+			t := d.now.AddDate(-unit.years*count, -unit.months*count, -unit.days*count)
+			return t.Add(-unit.duration * time.Duration(count)), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// withYear дополняет дату без года текущим годом. Дата, оказавшаяся
+// в будущем, относится к прошлому году: "28 декабря" в январе.
+func (d dateParser) withYear(t time.Time) time.Time {
+	t = t.UTC()
+	if t.Year() != 0 {
+		return t
+	}
+
+	t = t.AddDate(d.now.Year(), 0, 0)
+	if t.After(d.now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+
+	return t
+}
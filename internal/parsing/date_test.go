@@ -0,0 +1,149 @@
+package parsing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateParserParse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		source Source
+		value  string
+		want   time.Time
+	}{
+		{
+			name:  "iso",
+			value: "2023-12-12T10:30:00+03:00",
+			want:  time.Date(2023, time.December, 12, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "russian month",
+			value: "12 декабря 2023",
+			want:  time.Date(2023, time.December, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "russian month with year suffix",
+			value: "12 дек. 2023г.",
+			want:  time.Date(2023, time.December, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "russian time first",
+			value: "14:30, 12 декабря 2023 года",
+			want:  time.Date(2023, time.December, 12, 14, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "english",
+			value: "Dec 12, 2023",
+			want:  time.Date(2023, time.December, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "english with weekday and ordinal",
+			value: "Tuesday, December 12th, 2023 at 3:30 pm",
+			want:  time.Date(2023, time.December, 12, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "days ago",
+			value: "3 days ago",
+			want:  time.Date(2024, time.January, 7, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "an hour ago",
+			value: "an hour ago",
+			want:  time.Date(2024, time.January, 10, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "hours ago in russian",
+			value: "2 часа назад",
+			want:  time.Date(2024, time.January, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "year ago in russian",
+			value: "год назад",
+			want:  time.Date(2023, time.January, 10, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "years ago in russian",
+			value: "2 года назад",
+			want:  time.Date(2022, time.January, 10, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "many years ago in russian",
+			value: "5 лет назад",
+			want:  time.Date(2019, time.January, 10, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "russian year word",
+			value: "12 декабря 2023 г. в 14:30",
+			want:  time.Date(2023, time.December, 12, 14, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "minute ago in russian",
+			value: "минуту назад",
+			want:  time.Date(2024, time.January, 10, 11, 59, 0, 0, time.UTC),
+		},
+		{
+			name:  "yesterday with time",
+			value: "Вчера в 14:30",
+			want:  time.Date(2024, time.January, 9, 14, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "today",
+			value: "Today",
+			want:  time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "without year",
+			value: "5 января",
+			want:  time.Date(2024, time.January, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "without year in the past year",
+			value: "28 декабря",
+			want:  time.Date(2023, time.December, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "source format",
+			source: Source{DateFormat: "01/02/2006"},
+			value:  "12/11/2023",
+			want:   time.Date(2023, time.December, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "source format with russian month",
+			source: Source{DateFormat: "2006 Jan 2"},
+			value:  "2023, декабря 12",
+			want:   time.Date(2023, time.December, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "locale restricts month names",
+			source: Source{DateLocale: "en"},
+			value:  "12 декабря 2023",
+		},
+		{
+			name:  "unknown",
+			value: "на прошлой неделе",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dates := newDateParser(tc.source, now)
+
+			// act
+			got := dates.parse(tc.value)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package parsing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+// WellKnownFeedPaths пути, по которым ленты чаще всего публикуются
+// движками блогов. Проверяются, если страница не ссылается на ленту.
+var WellKnownFeedPaths = []string{
+	"feed/",
+	"rss.xml",
+	"feed.xml",
+	"atom.xml",
+	"index.xml",
+	"feed.atom",
+	"feed.json",
+	"/feed/",
+	"/rss.xml",
+	"/feed.xml",
+	"/atom.xml",
+	"/index.xml",
+}
+
+var feedContentTypes = map[string]string{
+	"application/rss+xml":   config.TypeRSS,
+	"application/rdf+xml":   config.TypeRSS,
+	"application/atom+xml":  config.TypeAtom,
+	"application/feed+json": config.TypeJSONFeed,
+	"application/json":      config.TypeJSONFeed,
+}
+
+// FeedLink ссылка на ленту, объявленная на странице.
+type FeedLink struct {
+	URL  string
+	Type string
+}
+
+// FindFeedLinks возвращает ленты, объявленные на странице через
+// <link rel="alternate">, в порядке их следования в документе.
+func FindFeedLinks(pageURL, body string) ([]FeedLink, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parser: unable to parse %s: %v", pageURL, err)
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("parser: invalid page url %s: %v", pageURL, err)
+	}
+
+	var links []FeedLink
+
+	doc.Find(`link[rel~="alternate"][href]`).Each(func(_ int, link *goquery.Selection) {
+		contentType := strings.ToLower(strings.TrimSpace(link.AttrOr("type", "")))
+		feedType, ok := feedContentTypes[contentType]
+		if !ok {
+			return
+		}
+
+		ref, err := url.Parse(strings.TrimSpace(link.AttrOr("href", "")))
+		if err != nil {
+			return
+		}
+
+		links = append(links, FeedLink{
+			URL:  base.ResolveReference(ref).String(),
+			Type: feedType,
+		})
+	})
+
+	return links, nil
+}
+
+// DetectFeedType определяет формат ленты по её содержимому
+// и возвращает соответствующий тип источника.
+func DetectFeedType(body string) (string, error) {
+	body = strings.TrimSpace(body)
+
+	if strings.HasPrefix(body, "{") {
+		var feed jsonFeed
+		if err := json.Unmarshal([]byte(body), &feed); err != nil {
+			return "", fmt.Errorf("%w: %v", errUnknownFeedFormat, err)
+		}
+		if !strings.Contains(feed.Version, "jsonfeed.org") {
+			return "", fmt.Errorf("%w: json without jsonfeed version", errUnknownFeedFormat)
+		}
+		return config.TypeJSONFeed, nil
+	}
+
+	root, err := feedRootElement(body)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errUnknownFeedFormat, err)
+	}
+
+	switch root {
+	case "rss", "RDF":
+		return config.TypeRSS, nil
+	case "feed":
+		return config.TypeAtom, nil
+	}
+
+	return "", fmt.Errorf("%w: root element %q", errUnknownFeedFormat, root)
+}
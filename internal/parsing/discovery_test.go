@@ -0,0 +1,92 @@
+package parsing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestFindFeedLinks(t *testing.T) {
+	t.Parallel()
+
+	body := `<html>
+<head>
+  <link rel="stylesheet" href="/style.css">
+  <link rel="alternate" type="application/rss+xml" title="RSS" href="/feed/">
+  <link rel="alternate" type="application/atom+xml" href="https://example.com/atom.xml">
+  <link rel="alternate" hreflang="en" href="https://example.com/en/">
+  <link rel="alternate" type="application/feed+json" href="feed.json">
+</head>
+<body></body>
+</html>`
+
+	// act
+	got, err := FindFeedLinks("https://example.com/blog/", body)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, []FeedLink{
+		{URL: "https://example.com/feed/", Type: config.TypeRSS},
+		{URL: "https://example.com/atom.xml", Type: config.TypeAtom},
+		{URL: "https://example.com/blog/feed.json", Type: config.TypeJSONFeed},
+	}, got)
+}
+
+func TestDetectFeedType(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "rss",
+			body: `<?xml version="1.0"?><rss version="2.0"><channel></channel></rss>`,
+			want: config.TypeRSS,
+		},
+		{
+			name: "atom",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`,
+			want: config.TypeAtom,
+		},
+		{
+			name: "jsonfeed",
+			body: `{"version": "https://jsonfeed.org/version/1.1", "items": []}`,
+			want: config.TypeJSONFeed,
+		},
+		{
+			name:    "html",
+			body:    `<!DOCTYPE html><html><body></body></html>`,
+			wantErr: true,
+		},
+		{
+			name:    "arbitrary json",
+			body:    `{"items": []}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got, err := DetectFeedType(tc.body)
+
+			// assert
+			if tc.wantErr {
+				require.ErrorIs(t, err, errUnknownFeedFormat)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
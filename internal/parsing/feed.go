@@ -0,0 +1,231 @@
+package parsing
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// Модели RSS 2.0 и RSS 1.0 (RDF). Элементы item в RSS 1.0 находятся
+// на одном уровне с channel, поэтому они собираются с обоих уровней.
+type rssFeed struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title   string  `xml:"title"`
+	Link    string  `xml:"link"`
+	GUID    rssGUID `xml:"guid"`
+	PubDate string  `xml:"pubDate"`
+	// Date дата публикации из пространства имён Dublin Core.
+	Date string `xml:"date"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	Title         string `json:"title"`
+	URL           string `json:"url"`
+	ExternalURL   string `json:"external_url"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+}
+
+var errUnknownFeedFormat = errors.New("unknown feed format")
+
+// ParseFeed извлекает статьи из ленты RSS, Atom или JSON Feed.
+// Формат определяется по содержимому, относительные ссылки
+// разрешаются относительно адреса источника.
+func (p *Parser) ParseFeed(source Source, body string) (ParseResult, error) {
+	var result ParseResult
+
+	articles, err := parseFeed(strings.TrimSpace(body))
+	if err != nil {
+		return result, fmt.Errorf("parser: unable to parse feed %s: %w", source.String(), err)
+	}
+
+	log.Printf("parser: %s feed parsing succeeded", source.String())
+
+	rewrites, err := compileRewrites(source)
+	if err != nil {
+		return result, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
+
+	baseURL, err := url.Parse(source.URL)
+	if err != nil {
+		return result, fmt.Errorf("parser: invalid url %s: %v", source.String(), err)
+	}
+
+	result.Matched = len(articles)
+	result.Articles = make([]Article, 0, len(articles))
+
+	for i, item := range articles {
+		article, ok := result.newArticle(i, source, rewrites, baseURL, item.Title, item.DetailURL)
+		if !ok {
+			continue
+		}
+
+		article.Published = item.Published
+
+		result.Articles = append(result.Articles, article)
+	}
+
+	return result, nil
+}
+
+func parseFeed(body string) ([]Article, error) {
+	if strings.HasPrefix(body, "{") {
+		return parseJSONFeed(body)
+	}
+
+	root, err := feedRootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss", "RDF":
+		return parseRSS(body)
+	case "feed":
+		return parseAtom(body)
+	}
+
+	return nil, fmt.Errorf("%w: root element %q", errUnknownFeedFormat, root)
+}
+
+func feedRootElement(body string) (string, error) {
+	decoder := newFeedDecoder(body)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+
+		if el, ok := token.(xml.StartElement); ok {
+			return el.Name.Local, nil
+		}
+	}
+}
+
+func newFeedDecoder(body string) *xml.Decoder {
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	decoder.Strict = false
+	// Ленты в кодировке, отличной от UTF-8, приводятся к UTF-8
+	// до разбора, поэтому объявление кодировки игнорируется.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder
+}
+
+func parseRSS(body string) ([]Article, error) {
+	var feed rssFeed
+	if err := newFeedDecoder(body).Decode(&feed); err != nil {
+		return nil, err
+	}
+
+	items := append(feed.Channel.Items, feed.Items...)
+	articles := make([]Article, 0, len(items))
+
+	for _, item := range items {
+		link := item.Link
+		if len(strings.TrimSpace(link)) == 0 && item.GUID.IsPermaLink != "false" {
+			link = item.GUID.Value
+		}
+
+		articles = append(articles, Article{
+			Title:     item.Title,
+			DetailURL: link,
+			Published: parseDate(item.PubDate, item.Date),
+		})
+	}
+
+	return articles, nil
+}
+
+func parseAtom(body string) ([]Article, error) {
+	var feed atomFeed
+	if err := newFeedDecoder(body).Decode(&feed); err != nil {
+		return nil, err
+	}
+
+	articles := make([]Article, 0, len(feed.Entries))
+
+	for _, entry := range feed.Entries {
+		articles = append(articles, Article{
+			Title:     entry.Title,
+			DetailURL: atomAlternateLink(entry.Links),
+			Published: parseDate(entry.Published, entry.Updated),
+		})
+	}
+
+	return articles, nil
+}
+
+// atomAlternateLink возвращает ссылку на статью. Ссылка без rel
+// по спецификации Atom равнозначна rel="alternate".
+func atomAlternateLink(links []atomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+func parseJSONFeed(body string) ([]Article, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal([]byte(body), &feed); err != nil {
+		return nil, err
+	}
+
+	articles := make([]Article, 0, len(feed.Items))
+
+	for _, item := range feed.Items {
+		link := item.URL
+		if len(link) == 0 {
+			link = item.ExternalURL
+		}
+
+		articles = append(articles, Article{
+			Title:     item.Title,
+			DetailURL: link,
+			Published: parseDate(item.DatePublished, item.DateModified),
+		})
+	}
+
+	return articles, nil
+}
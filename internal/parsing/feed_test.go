@@ -0,0 +1,195 @@
+package parsing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserParseFeed(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		source Source
+		body   string
+		want   []Article
+	}{
+		{
+			name: "rss",
+			source: Source{
+				Name: "https://dave.cheney.net/",
+				URL:  "https://dave.cheney.net/feed",
+			},
+			want: []Article{
+				{
+					Title:     "Introducing Structured Logging",
+					DetailURL: "https://dave.cheney.net/2023/09/01/introducing-structured-logging",
+					Published: time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC),
+				},
+				{
+					// Ссылка берётся из guid, если link отсутствует.
+					Title:     "Fuzzing in Go",
+					DetailURL: "https://dave.cheney.net/2023/08/15/fuzzing-in-go",
+					Published: time.Date(2023, 8, 15, 7, 30, 0, 0, time.UTC),
+				},
+			},
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+<channel>
+  <title>Dave Cheney</title>
+  <link>https://dave.cheney.net</link>
+  <atom:link href="https://dave.cheney.net/feed" rel="self" type="application/rss+xml" />
+  <item>
+    <title>Introducing
+      Structured Logging</title>
+    <link>https://dave.cheney.net/2023/09/01/introducing-structured-logging</link>
+    <pubDate>Fri, 01 Sep 2023 10:00:00 +0000</pubDate>
+    <guid isPermaLink="false">https://dave.cheney.net/?p=1001</guid>
+  </item>
+  <item>
+    <title>Fuzzing in Go</title>
+    <pubDate>Tue, 15 Aug 2023 10:30:00 +0300</pubDate>
+    <guid>https://dave.cheney.net/2023/08/15/fuzzing-in-go</guid>
+  </item>
+  <item>
+    <!-- Будет пропущено - нет ссылки -->
+    <title>No link</title>
+    <guid isPermaLink="false">1003</guid>
+  </item>
+</channel>
+</rss>`,
+		},
+		{
+			name: "rdf",
+			source: Source{
+				Name: "https://example.com/",
+				URL:  "https://example.com/index.rdf",
+			},
+			want: []Article{
+				{
+					Title:     "First post",
+					DetailURL: "https://example.com/first",
+					Published: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			body: `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel rdf:about="https://example.com/">
+    <title>Example</title>
+  </channel>
+  <item rdf:about="https://example.com/first">
+    <title>First post</title>
+    <link>https://example.com/first</link>
+    <dc:date>2023-12-01T00:00:00Z</dc:date>
+  </item>
+</rdf:RDF>`,
+		},
+		{
+			name: "atom",
+			source: Source{
+				Name: "https://go.dev/blog/",
+				URL:  "https://go.dev/blog/feed.atom",
+			},
+			want: []Article{
+				{
+					Title:     "Go Developer Survey 2023 H2 Results",
+					DetailURL: "https://go.dev/blog/survey2023-h2-results",
+					Published: time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					// Относительная ссылка без rel, дата берётся из updated.
+					Title:     "Finding unreachable functions with deadcode",
+					DetailURL: "https://go.dev/blog/deadcode",
+					Published: time.Date(2023, 12, 12, 18, 0, 0, 0, time.UTC),
+				},
+			},
+			body: `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>The Go Blog</title>
+  <link href="https://go.dev/blog/"></link>
+  <entry>
+    <title>Go Developer Survey 2023 H2 Results</title>
+    <link rel="edit" href="https://go.dev/admin/survey2023-h2-results"></link>
+    <link rel="alternate" href="https://go.dev/blog/survey2023-h2-results"></link>
+    <published>2023-12-05T00:00:00+00:00</published>
+    <updated>2023-12-05T12:00:00+00:00</updated>
+  </entry>
+  <entry>
+    <title>Finding unreachable functions with deadcode</title>
+    <link href="/blog/deadcode"></link>
+    <updated>2023-12-12T20:00:00+02:00</updated>
+  </entry>
+</feed>`,
+		},
+		{
+			name: "jsonfeed",
+			source: Source{
+				Name: "https://example.org/",
+				URL:  "https://example.org/feed.json",
+			},
+			want: []Article{
+				{
+					Title:     "Hello, world",
+					DetailURL: "https://example.org/hello",
+					Published: time.Date(2023, 11, 2, 9, 0, 0, 0, time.UTC),
+				},
+				{
+					Title:     "Linked post",
+					DetailURL: "https://other.example.com/post",
+				},
+			},
+			body: `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Example",
+  "items": [
+    {
+      "id": "1",
+      "title": "Hello, world",
+      "url": "https://example.org/hello",
+      "date_published": "2023-11-02T09:00:00Z"
+    },
+    {
+      "id": "2",
+      "title": "Linked post",
+      "external_url": "https://other.example.com/post"
+    },
+    {
+      "id": "3",
+      "url": "https://example.org/untitled"
+    }
+  ]
+}`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.ParseFeed(tc.source, tc.body)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.Articles)
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		parser := NewParser()
+
+		// act
+		_, err := parser.ParseFeed(Source{URL: "https://example.com/"}, `<html><body></body></html>`)
+
+		// assert
+		require.ErrorIs(t, err, errUnknownFeedFormat)
+	})
+}
@@ -0,0 +1,137 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+const regexpRulePrefix = "re:"
+
+// filterRule скомпилированное правило фильтрации.
+type filterRule struct {
+	field string
+	rule  string
+	match func(string) bool
+}
+
+// Filter отбирает статьи источника по правилам include и exclude.
+type Filter struct {
+	include []filterRule
+	exclude []filterRule
+}
+
+// NewFilter компилирует правила фильтрации источника.
+func NewFilter(include, exclude config.FilterRules) (*Filter, error) {
+	f := &Filter{}
+
+	var err error
+
+	if f.include, err = compileFilterRules(include); err != nil {
+		return nil, fmt.Errorf("include: %v", err)
+	}
+
+	if f.exclude, err = compileFilterRules(exclude); err != nil {
+		return nil, fmt.Errorf("exclude: %v", err)
+	}
+
+	return f, nil
+}
+
+// Match сообщает, нужно ли сохранять статью, и, если нет, - почему.
+func (f *Filter) Match(article Article) (bool, string) {
+	for _, rule := range f.exclude {
+		if rule.matches(article) {
+			return false, fmt.Sprintf("exclude %s %q", rule.field, rule.rule)
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true, ""
+	}
+
+	for _, rule := range f.include {
+		if rule.matches(article) {
+			return true, ""
+		}
+	}
+
+	return false, "no include rule matched"
+}
+
+// MatchURL проверяет статью по правилам для адреса до загрузки её страницы.
+// false означает, что статья будет отброшена при любом заголовке.
+func (f *Filter) MatchURL(detailURL string) bool {
+	for _, rule := range f.exclude {
+		if rule.field == "url" && rule.match(detailURL) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	// Правило для заголовка может сработать после загрузки страницы.
+	for _, rule := range f.include {
+		if rule.field != "url" || rule.match(detailURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r filterRule) matches(article Article) bool {
+	if r.field == "title" {
+		return r.match(article.Title)
+	}
+	return r.match(article.DetailURL)
+}
+
+func compileFilterRules(rules config.FilterRules) ([]filterRule, error) {
+	compiled := make([]filterRule, 0, len(rules.Title)+len(rules.URL))
+
+	fields := []struct {
+		name  string
+		rules []string
+	}{
+		{name: "title", rules: rules.Title},
+		{name: "url", rules: rules.URL},
+	}
+
+	for _, field := range fields {
+		for _, rule := range field.rules {
+			match, err := compileFilterRule(rule)
+			if err != nil {
+				return nil, fmt.Errorf("%s rule %q: %v", field.name, rule, err)
+			}
+
+			compiled = append(compiled, filterRule{field: field.name, rule: rule, match: match})
+		}
+	}
+
+	return compiled, nil
+}
+
+func compileFilterRule(rule string) (func(string) bool, error) {
+	if expr, ok := strings.CutPrefix(rule, regexpRulePrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+
+	keyword := strings.ToLower(strings.TrimSpace(rule))
+	if len(keyword) == 0 {
+		return nil, errors.New("empty keyword")
+	}
+
+	return func(s string) bool {
+		return strings.Contains(strings.ToLower(s), keyword)
+	}, nil
+}
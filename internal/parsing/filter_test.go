@@ -0,0 +1,167 @@
+package parsing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestFilterMatch(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		include    config.FilterRules
+		exclude    config.FilterRules
+		article    Article
+		wantOK     bool
+		wantReason string
+	}{
+		{
+			name:    "no rules",
+			article: Article{Title: "Anything", DetailURL: "https://example.com/post"},
+			wantOK:  true,
+		},
+		{
+			name:    "include title keyword ignores case",
+			include: config.FilterRules{Title: []string{"golang"}},
+			article: Article{Title: "Profiling GoLang services", DetailURL: "https://example.com/post"},
+			wantOK:  true,
+		},
+		{
+			name:       "include does not match",
+			include:    config.FilterRules{Title: []string{"golang"}, URL: []string{"/go/"}},
+			article:    Article{Title: "Rust in production", DetailURL: "https://example.com/rust/post"},
+			wantReason: "no include rule matched",
+		},
+		{
+			name:    "include url regexp",
+			include: config.FilterRules{URL: []string{`re:^https://aws\.amazon\.com/blogs/`}},
+			article: Article{Title: "Post", DetailURL: "https://aws.amazon.com/blogs/architecture/post"},
+			wantOK:  true,
+		},
+		{
+			name:       "exclude url keyword",
+			exclude:    config.FilterRules{URL: []string{"/ru/"}},
+			article:    Article{Title: "Post", DetailURL: "https://aws.amazon.com/ru/blogs/architecture/post"},
+			wantReason: `exclude url "/ru/"`,
+		},
+		{
+			name:       "exclude wins over include",
+			include:    config.FilterRules{Title: []string{"kubernetes"}},
+			exclude:    config.FilterRules{Title: []string{`re:(?i)^\[sponsored\]`}},
+			article:    Article{Title: "[Sponsored] Kubernetes at scale", DetailURL: "https://example.com/post"},
+			wantReason: `exclude title "re:(?i)^\\[sponsored\\]"`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := NewFilter(tc.include, tc.exclude)
+			require.NoError(t, err)
+
+			// act
+			ok, reason := filter.Match(tc.article)
+
+			// assert
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.wantReason, reason)
+		})
+	}
+}
+
+func TestNewFilterInvalidRules(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		include config.FilterRules
+		exclude config.FilterRules
+	}{
+		{name: "invalid regexp", include: config.FilterRules{Title: []string{"re:("}}},
+		{name: "empty keyword", exclude: config.FilterRules{URL: []string{"  "}}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			_, err := NewFilter(tc.include, tc.exclude)
+
+			// assert
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFilterMatchURL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		include   config.FilterRules
+		exclude   config.FilterRules
+		detailURL string
+		want      bool
+	}{
+		{
+			name:      "no rules",
+			detailURL: "https://example.com/post",
+			want:      true,
+		},
+		{
+			name:      "exclude url keyword",
+			exclude:   config.FilterRules{URL: []string{"/ru/"}},
+			detailURL: "https://aws.amazon.com/ru/blogs/post",
+		},
+		{
+			name:      "exclude title is checked after enrichment",
+			exclude:   config.FilterRules{Title: []string{"sponsored"}},
+			detailURL: "https://example.com/sponsored/post",
+			want:      true,
+		},
+		{
+			name:      "include url does not match",
+			include:   config.FilterRules{URL: []string{"/go/"}},
+			detailURL: "https://example.com/rust/post",
+		},
+		{
+			name:      "include url matches",
+			include:   config.FilterRules{URL: []string{"/go/"}},
+			detailURL: "https://example.com/go/post",
+			want:      true,
+		},
+		{
+			name:      "include title may match after enrichment",
+			include:   config.FilterRules{Title: []string{"golang"}, URL: []string{"/go/"}},
+			detailURL: "https://example.com/rust/post",
+			want:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := NewFilter(tc.include, tc.exclude)
+			require.NoError(t, err)
+
+			// act
+			ok := filter.MatchURL(tc.detailURL)
+
+			// assert
+			require.Equal(t, tc.want, ok)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package parsing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+)
+
+var errInvalidJSONPath = errors.New("invalid jsonpath")
+
+// jsonPaths скомпилированные выражения JSONPath источника.
+// Незаданные необязательные выражения остаются nil.
+type jsonPaths struct {
+	items   gval.Evaluable
+	title   gval.Evaluable
+	detail  gval.Evaluable
+	date    gval.Evaluable
+	author  gval.Evaluable
+	summary gval.Evaluable
+	image   gval.Evaluable
+}
+
+func compileJSONPaths(source Source) (jsonPaths, error) {
+	var paths jsonPaths
+
+	fields := []struct {
+		name     string
+		expr     string
+		required bool
+		dst      *gval.Evaluable
+	}{
+		{name: "article", expr: source.ArticleSelector, required: true, dst: &paths.items},
+		{name: "title", expr: source.TitleSelector, required: true, dst: &paths.title},
+		{name: "detail", expr: source.DetailSelector, required: true, dst: &paths.detail},
+		{name: "date", expr: source.DateSelector, dst: &paths.date},
+		{name: "author", expr: source.AuthorSelector, dst: &paths.author},
+		{name: "summary", expr: source.SummarySelector, dst: &paths.summary},
+		{name: "image", expr: source.ImageSelector, dst: &paths.image},
+	}
+
+	for _, field := range fields {
+		expr := strings.TrimSpace(field.expr)
+		if len(expr) == 0 {
+			if field.required {
+				return paths, fmt.Errorf("%s selector: %w", field.name, errEmptySelector)
+			}
+			continue
+		}
+
+		eval, err := jsonpath.New(expr)
+		if err != nil {
+			return paths, fmt.Errorf("%s selector: %w %q: %v", field.name, errInvalidJSONPath, expr, err)
+		}
+
+		*field.dst = eval
+	}
+
+	return paths, nil
+}
+
+// ParseJSON извлекает статьи из ответа JSON API. Селектор статей
+// выбирает массив элементов, остальные выражения вычисляются
+// относительно элемента: "$.title", "$.author.name". Относительные
+// ссылки разрешаются относительно адреса источника.
+func (p *Parser) ParseJSON(source Source, body string) (ParseResult, error) {
+	var result ParseResult
+
+	var data any
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return result, fmt.Errorf("parser: unable to parse json %s: %v", source.String(), err)
+	}
+
+	log.Printf("parser: %s json parsing succeeded", source.String())
+
+	paths, err := compileJSONPaths(source)
+	if err != nil {
+		return result, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
+
+	rewrites, err := compileRewrites(source)
+	if err != nil {
+		return result, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
+
+	baseURL, err := url.Parse(source.URL)
+	if err != nil {
+		return result, fmt.Errorf("parser: invalid url %s: %v", source.String(), err)
+	}
+
+	found, err := paths.items(context.Background(), data)
+	if err != nil {
+		return result, fmt.Errorf("parser: %s article selector: %v", source.String(), err)
+	}
+
+	// Одиночный объект - одна карточка. null и скалярные значения
+	// означают, что селектор статей указывает не туда.
+	var items []any
+	switch v := found.(type) {
+	case []any:
+		items = v
+	case map[string]any:
+		items = []any{v}
+	}
+
+	result.Matched = len(items)
+	result.Articles = make([]Article, 0, len(items))
+
+	dates := newDateParser(source, p.now())
+
+	for i, item := range items {
+		article, ok := result.newArticle(i, source, rewrites, baseURL, jsonValue(paths.title, item), jsonValue(paths.detail, item))
+		if !ok {
+			continue
+		}
+
+		article.Published = jsonDate(dates, paths.date, item)
+		article.Author = formatTitle(jsonValue(paths.author, item))
+		article.Summary = formatTitle(jsonValue(paths.summary, item))
+
+		if image := strings.TrimSpace(jsonValue(paths.image, item)); len(image) > 0 {
+			if imageURL, err := resolveLink(baseURL, image); err == nil {
+				article.ImageURL = imageURL
+			}
+		}
+
+		result.Articles = append(result.Articles, article)
+	}
+
+	return result, nil
+}
+
+// jsonEval вычисляет выражение относительно элемента. Отсутствующее
+// поле не является ошибкой, списки сводятся к первому значению.
+func jsonEval(eval gval.Evaluable, item any) any {
+	if eval == nil {
+		return nil
+	}
+
+	value, err := eval(context.Background(), item)
+	if err != nil {
+		return nil
+	}
+
+	if list, ok := value.([]any); ok {
+		if len(list) == 0 {
+			return nil
+		}
+		return list[0]
+	}
+
+	return value
+}
+
+func jsonValue(eval gval.Evaluable, item any) string {
+	switch v := jsonEval(eval, item).(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// jsonDate разбирает дату из строки или из числа - времени Unix
+// в секундах или миллисекундах.
+func jsonDate(dates dateParser, eval gval.Evaluable, item any) time.Time {
+	switch v := jsonEval(eval, item).(type) {
+	case string:
+		if seconds, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return unixDate(float64(seconds))
+		}
+		return dates.parse(v)
+	case float64:
+		return unixDate(v)
+	}
+	return time.Time{}
+}
+
+func unixDate(v float64) time.Time {
+	if v <= 0 {
+		return time.Time{}
+	}
+
+	// Значения больше 10^11 - миллисекунды: в секундах это 5138 год.
+	if v > 1e11 {
+		return time.UnixMilli(int64(v)).UTC()
+	}
+
+	sec, frac := math.Modf(v)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
@@ -0,0 +1,249 @@
+package parsing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+const bodyWordPressPosts = `[
+  {
+    "id": 10512,
+    "date": "2023-12-12T15:00:00",
+    "date_gmt": "2023-12-12T12:00:00",
+    "link": "https://engineering.example.com/2023/12/12/scaling-kafka/",
+    "title": {"rendered": "Scaling Kafka\n at Example"},
+    "excerpt": {"rendered": "How we moved to tiered storage."},
+    "_embedded": {"author": [{"name": "Jane Doe"}]}
+  },
+  {
+    "id": 10498,
+    "date_gmt": "2023-12-05T09:30:00",
+    "link": "https://engineering.example.com/2023/12/05/observability/?utm_source=api",
+    "title": {"rendered": "Observability pipeline"},
+    "excerpt": {"rendered": ""}
+  }
+]`
+
+const bodySPAListing = `{
+  "data": {
+    "posts": [
+      {
+        "slug": "/blog/zero-downtime-migrations",
+        "headline": "Zero downtime migrations",
+        "publishedAt": 1702288800000,
+        "cover": {"url": "/static/covers/migrations.png"}
+      },
+      {
+        "slug": "/blog/no-title"
+      },
+      {
+        "slug": "/blog/feature-flags",
+        "headline": "Feature flags in Go",
+        "publishedAt": "1701684000"
+      }
+    ]
+  }
+}`
+
+func TestParserParseJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		source Source
+		body   string
+		want   []Article
+	}{
+		{
+			name: "wordpress rest api",
+			source: NewSource("Example engineering", "https://engineering.example.com/", config.SourceConfig{
+				Type:            config.TypeJSON,
+				APIURL:          "https://engineering.example.com/wp-json/wp/v2/posts?_embed",
+				ArticleSelector: "$[*]",
+				TitleSelector:   "$.title.rendered",
+				DetailSelector:  "$.link",
+				DateSelector:    "$.date_gmt",
+				AuthorSelector:  "$._embedded.author[0].name",
+				SummarySelector: "$.excerpt.rendered",
+			}),
+			body: bodyWordPressPosts,
+			want: []Article{
+				{
+					Title:     "Scaling Kafka at Example",
+					DetailURL: "https://engineering.example.com/2023/12/12/scaling-kafka/",
+					Published: time.Date(2023, 12, 12, 12, 0, 0, 0, time.UTC),
+					Author:    "Jane Doe",
+					Summary:   "How we moved to tiered storage.",
+				},
+				{
+					Title:     "Observability pipeline",
+					DetailURL: "https://engineering.example.com/2023/12/05/observability/",
+					Published: time.Date(2023, 12, 5, 9, 30, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "spa listing with relative links and unix dates",
+			source: NewSource("Example blog", "https://example.com/blog", config.SourceConfig{
+				Type:            config.TypeJSON,
+				ArticleSelector: "$.data.posts",
+				TitleSelector:   "$.headline",
+				DetailSelector:  "$.slug",
+				DateSelector:    "$.publishedAt",
+				ImageSelector:   "$.cover.url",
+			}),
+			body: bodySPAListing,
+			want: []Article{
+				{
+					Title:     "Zero downtime migrations",
+					DetailURL: "https://example.com/blog/zero-downtime-migrations",
+					Published: time.Date(2023, 12, 11, 10, 0, 0, 0, time.UTC),
+					ImageURL:  "https://example.com/static/covers/migrations.png",
+				},
+				{
+					Title:     "Feature flags in Go",
+					DetailURL: "https://example.com/blog/feature-flags",
+					Published: time.Date(2023, 12, 4, 10, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "captured response",
+			source: NewSource("Example blog", "https://example.com/blog", config.SourceConfig{
+				Capture:         "https://example.com/api/posts*",
+				ArticleSelector: "$.data.posts[*]",
+				TitleSelector:   "$.headline",
+				DetailSelector:  "$.slug",
+			}),
+			body: bodySPAListing,
+			want: []Article{
+				{
+					Title:     "Zero downtime migrations",
+					DetailURL: "https://example.com/blog/zero-downtime-migrations",
+				},
+				{
+					Title:     "Feature flags in Go",
+					DetailURL: "https://example.com/blog/feature-flags",
+				},
+			},
+		},
+		{
+			name: "no items",
+			source: NewSource("Example blog", "https://example.com/blog", config.SourceConfig{
+				Type:            config.TypeJSON,
+				ArticleSelector: "$.data.posts[*]",
+				TitleSelector:   "$.headline",
+				DetailSelector:  "$.slug",
+			}),
+			body: `{"data": {"posts": []}}`,
+			want: []Article{},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.ParseJSON(tc.source, tc.body)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.Articles)
+		})
+	}
+}
+
+func TestParserParseJSONMatched(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		selector string
+		body     string
+		want     int
+	}{
+		{name: "array", selector: "$.data.posts", body: `{"data": {"posts": [{"title": "A"}, {"title": "B"}]}}`, want: 2},
+		{name: "single object", selector: "$.data.post", body: `{"data": {"post": {"title": "A"}}}`, want: 1},
+		{name: "null", selector: "$.data.posts", body: `{"data": {"posts": null}}`, want: 0},
+		{name: "scalar", selector: "$.data.total", body: `{"data": {"total": 42}}`, want: 0},
+		{name: "string", selector: "$.status", body: `{"status": "ok"}`, want: 0},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewSource("Example blog", "https://example.com/blog", config.SourceConfig{
+				Type:            config.TypeJSON,
+				ArticleSelector: tc.selector,
+				TitleSelector:   "$.title",
+				DetailSelector:  "$.url",
+			})
+
+			// act
+			got, err := NewParser().ParseJSON(source, tc.body)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.Matched)
+		})
+	}
+}
+
+func TestParserParseJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		source Source
+		body   string
+	}{
+		{
+			name: "invalid json",
+			source: NewSource("Example blog", "https://example.com/blog", config.SourceConfig{
+				Type:            config.TypeJSON,
+				ArticleSelector: "$[*]",
+				TitleSelector:   "$.title",
+				DetailSelector:  "$.url",
+			}),
+			body: `<html>Not found</html>`,
+		},
+		{
+			name: "missing items",
+			source: NewSource("Example blog", "https://example.com/blog", config.SourceConfig{
+				Type:            config.TypeJSON,
+				ArticleSelector: "$.data.posts",
+				TitleSelector:   "$.title",
+				DetailSelector:  "$.url",
+			}),
+			body: `{"error": "rate limited"}`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			_, err := parser.ParseJSON(tc.source, tc.body)
+
+			// assert
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var errUnsupportedScheme = errors.New("unsupported scheme")
+
+// documentBaseURL возвращает базовый адрес документа по RFC 3986:
+// адрес страницы, переопределённый первым <base href>, если он есть.
+func documentBaseURL(pageURL string, doc *goquery.Document) (*url.URL, error) {
+	base, err := url.Parse(strings.TrimSpace(pageURL))
+	if err != nil {
+		return nil, err
+	}
+
+	href, ok := doc.Find("base[href]").First().Attr("href")
+	if !ok {
+		return base, nil
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		// Браузеры игнорируют некорректный <base>, поступаем так же.
+		return base, nil
+	}
+
+	return base.ResolveReference(ref), nil
+}
+
+// resolveLink разрешает ссылку относительно базового адреса,
+// включая ссылки вида "../post", "post" и "//host/path".
+// Ссылки, не ведущие на http(s)-ресурс, отклоняются.
+func resolveLink(base *url.URL, link string) (string, error) {
+	ref, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", err
+	}
+
+	resolved := base.ResolveReference(ref)
+
+	switch resolved.Scheme {
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("%w: %q", errUnsupportedScheme, resolved.Scheme)
+	}
+
+	return resolved.String(), nil
+}
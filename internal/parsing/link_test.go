@@ -0,0 +1,195 @@
+package parsing
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveLink(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		base    string
+		link    string
+		want    string
+		wantErr bool
+	}{
+		{
+			// research.swtch.com
+			name: "relative to root directory",
+			base: "https://research.swtch.com/",
+			link: "testing",
+			want: "https://research.swtch.com/testing",
+		},
+		{
+			name: "relative to nested directory",
+			base: "https://example.com/blog/",
+			link: "testing",
+			want: "https://example.com/blog/testing",
+		},
+		{
+			name: "relative to nested page",
+			base: "https://example.com/blog/index.html",
+			link: "testing",
+			want: "https://example.com/blog/testing",
+		},
+		{
+			name: "dot segment",
+			base: "https://example.com/blog/",
+			link: "./testing",
+			want: "https://example.com/blog/testing",
+		},
+		{
+			name: "parent directory",
+			base: "https://example.com/blog/go/",
+			link: "../post",
+			want: "https://example.com/blog/post",
+		},
+		{
+			name: "parent directory above root",
+			base: "https://example.com/blog/",
+			link: "../../post",
+			want: "https://example.com/post",
+		},
+		{
+			// dropbox.tech
+			name: "absolute path",
+			base: "https://dropbox.tech/infrastructure",
+			link: "/infrastructure/post",
+			want: "https://dropbox.tech/infrastructure/post",
+		},
+		{
+			name: "protocol relative",
+			base: "https://example.com/blog/",
+			link: "//cdn.example.org/post",
+			want: "https://cdn.example.org/post",
+		},
+		{
+			// highscalability.com
+			name: "protocol relative keeps http",
+			base: "http://highscalability.com/",
+			link: "//highscalability.com/blog/post.html",
+			want: "http://highscalability.com/blog/post.html",
+		},
+		{
+			// eli.thegreenplace.net
+			name: "absolute",
+			base: "https://eli.thegreenplace.net/tag/go",
+			link: "https://eli.thegreenplace.net/2023/post/",
+			want: "https://eli.thegreenplace.net/2023/post/",
+		},
+		{
+			name: "query only",
+			base: "https://example.com/blog/list?page=1",
+			link: "?page=2",
+			want: "https://example.com/blog/list?page=2",
+		},
+		{
+			// blog.booking.com
+			name: "fragment in base",
+			base: "https://blog.booking.com/#development",
+			link: "post.html",
+			want: "https://blog.booking.com/post.html",
+		},
+		{
+			name: "surrounding whitespace",
+			base: "https://example.com/",
+			link: "\n   post \n",
+			want: "https://example.com/post",
+		},
+		{
+			name:    "mailto",
+			base:    "https://example.com/",
+			link:    "mailto:author@example.com",
+			wantErr: true,
+		},
+		{
+			name:    "javascript",
+			base:    "https://example.com/",
+			link:    "javascript:void(0)",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			base, err := url.Parse(tc.base)
+			require.NoError(t, err)
+
+			// act
+			got, err := resolveLink(base, tc.link)
+
+			// assert
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDocumentBaseURL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		pageURL string
+		body    string
+		want    string
+	}{
+		{
+			name:    "no base",
+			pageURL: "https://example.com/blog/",
+			body:    `<html><head></head><body></body></html>`,
+			want:    "https://example.com/blog/",
+		},
+		{
+			name:    "absolute base",
+			pageURL: "https://example.com/blog/",
+			body:    `<html><head><base href="https://static.example.com/posts/"></head></html>`,
+			want:    "https://static.example.com/posts/",
+		},
+		{
+			name:    "relative base",
+			pageURL: "https://example.com/blog/go/",
+			body:    `<html><head><base href="../"></head></html>`,
+			want:    "https://example.com/blog/",
+		},
+		{
+			name:    "base without href",
+			pageURL: "https://example.com/blog/",
+			body:    `<html><head><base target="_blank"></head></html>`,
+			want:    "https://example.com/blog/",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			// act
+			got, err := documentBaseURL(tc.pageURL, doc)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.String())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package parsing
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// PageMeta метаданные страницы статьи из OpenGraph и мета-тегов.
+type PageMeta struct {
+	Title        string
+	Description  string
+	ImageURL     string
+	Published    time.Time
+	CanonicalURL string
+}
+
+// ParseMeta извлекает метаданные со страницы статьи.
+func (p *Parser) ParseMeta(pageURL, body string) (PageMeta, error) {
+	var meta PageMeta
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return meta, fmt.Errorf("parser: unable to parse %s: %v", pageURL, err)
+	}
+
+	baseURL, err := documentBaseURL(pageURL, doc)
+	if err != nil {
+		return meta, fmt.Errorf("parser: invalid url %s: %v", pageURL, err)
+	}
+
+	meta.Title = formatTitle(metaContent(doc, "og:title", "twitter:title"))
+	if len(meta.Title) == 0 {
+		meta.Title = formatTitle(doc.Find("head title").First().Text())
+	}
+	meta.Description = formatTitle(metaContent(doc, "og:description", "twitter:description", "description"))
+	meta.Published = parseDate(metaContent(doc, "article:published_time", "og:published_time", "datePublished"))
+
+	if image := metaContent(doc, "og:image", "og:image:url", "twitter:image"); len(image) > 0 {
+		if imageURL, err := resolveLink(baseURL, image); err == nil {
+			meta.ImageURL = imageURL
+		}
+	}
+
+	canonical := strings.TrimSpace(doc.Find(`link[rel~="canonical"][href]`).First().AttrOr("href", ""))
+	if len(canonical) == 0 {
+		canonical = metaContent(doc, "og:url")
+	}
+
+	if len(canonical) > 0 {
+		if canonicalURL, err := resolveLink(baseURL, canonical); err == nil {
+			meta.CanonicalURL = canonicalURL
+		}
+	}
+
+	return meta, nil
+}
+
+// metaContent возвращает содержимое первого найденного мета-тега.
+// OpenGraph использует атрибут property, остальные теги - name.
+func metaContent(doc *goquery.Document, names ...string) string {
+	for _, name := range names {
+		for _, attr := range []string{"property", "name", "itemprop"} {
+			selector := fmt.Sprintf(`meta[%s=%q][content]`, attr, name)
+			content := strings.TrimSpace(doc.Find(selector).First().AttrOr("content", ""))
+			if len(content) > 0 {
+				return content
+			}
+		}
+	}
+
+	return ""
+}
+
+// Enrich дополняет статью метаданными страницы. Значения, извлечённые
+// из карточки списка, имеют приоритет.
+func (a Article) Enrich(meta PageMeta) Article {
+	if len(a.Title) == 0 {
+		a.Title = meta.Title
+	}
+
+	if len(a.Summary) == 0 {
+		a.Summary = meta.Description
+	}
+
+	if len(a.ImageURL) == 0 {
+		a.ImageURL = meta.ImageURL
+	}
+
+	if a.Published.IsZero() {
+		a.Published = meta.Published
+	}
+
+	if len(a.CanonicalURL) == 0 {
+		a.CanonicalURL = meta.CanonicalURL
+	}
+
+	a.Enriched = true
+
+	return a
+}
@@ -0,0 +1,142 @@
+package parsing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserParseMeta(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		pageURL string
+		body    string
+		want    PageMeta
+	}{
+		{
+			name:    "opengraph",
+			pageURL: "https://dropbox.tech/infrastructure/post?utm_source=feed",
+			body: `<html>
+<head>
+  <meta property="og:title" content="Post title">
+  <meta property="og:description" content="Post
+    description">
+  <meta property="og:image" content="/images/cover.png">
+  <meta property="article:published_time" content="2023-12-11T12:30:00+03:00">
+  <link rel="canonical" href="https://dropbox.tech/infrastructure/post">
+</head>
+<body></body>
+</html>`,
+			want: PageMeta{
+				Title:        "Post title",
+				Description:  "Post description",
+				ImageURL:     "https://dropbox.tech/images/cover.png",
+				Published:    time.Date(2023, 12, 11, 9, 30, 0, 0, time.UTC),
+				CanonicalURL: "https://dropbox.tech/infrastructure/post",
+			},
+		},
+		{
+			name:    "fallback tags",
+			pageURL: "https://example.com/post",
+			body: `<html>
+<head>
+  <meta name="twitter:title" content="Twitter title">
+  <meta name="description" content="Meta description">
+  <meta name="twitter:image" content="https://cdn.example.com/cover.png">
+  <meta property="og:url" content="/canonical">
+</head>
+<body></body>
+</html>`,
+			want: PageMeta{
+				Title:        "Twitter title",
+				Description:  "Meta description",
+				ImageURL:     "https://cdn.example.com/cover.png",
+				CanonicalURL: "https://example.com/canonical",
+			},
+		},
+		{
+			name:    "document title",
+			pageURL: "https://example.com/post",
+			body:    `<html><head><title>Title</title></head><body></body></html>`,
+			want:    PageMeta{Title: "Title"},
+		},
+		{
+			name:    "no meta",
+			pageURL: "https://example.com/post",
+			body:    `<html><head></head><body></body></html>`,
+			want:    PageMeta{},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.ParseMeta(tc.pageURL, tc.body)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestArticleEnrich(t *testing.T) {
+	t.Parallel()
+
+	meta := PageMeta{
+		Title:        "Meta title",
+		Description:  "Meta description",
+		ImageURL:     "https://example.com/meta.png",
+		Published:    time.Date(2023, 12, 11, 0, 0, 0, 0, time.UTC),
+		CanonicalURL: "https://example.com/post",
+	}
+
+	t.Run("fills missing fields", func(t *testing.T) {
+		t.Parallel()
+
+		article := Article{Title: "Title", DetailURL: "https://example.com/post?ref=list"}
+
+		// act
+		got := article.Enrich(meta)
+
+		// assert
+		require.Equal(t, Article{
+			Title:        "Title",
+			DetailURL:    "https://example.com/post?ref=list",
+			Published:    meta.Published,
+			Summary:      meta.Description,
+			ImageURL:     meta.ImageURL,
+			CanonicalURL: meta.CanonicalURL,
+			Enriched:     true,
+		}, got)
+	})
+
+	t.Run("keeps listing values", func(t *testing.T) {
+		t.Parallel()
+
+		article := Article{
+			Title:     "Title",
+			DetailURL: "https://example.com/post",
+			Published: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+			Summary:   "Summary",
+			ImageURL:  "https://example.com/card.png",
+		}
+
+		// act
+		got := article.Enrich(meta)
+
+		// assert
+		article.CanonicalURL = meta.CanonicalURL
+		article.Enriched = true
+		require.Equal(t, article, got)
+	})
+}
@@ -0,0 +1,155 @@
+package parsing
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+// trackingParams параметры отслеживания, которые удаляются всегда,
+// если источник явно не сохраняет их через URLRules.Keep.
+var trackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"gbraid",
+	"wbraid",
+	"msclkid",
+	"yclid",
+	"ysclid",
+	"igshid",
+	"mc_cid",
+	"mc_eid",
+	"_hsenc",
+	"_hsmi",
+	"_ga",
+	"_gl",
+	"mkt_tok",
+	"ref_src",
+	"ref_url",
+}
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// NormalizeURL приводит адрес статьи к каноническому виду, чтобы одна
+// и та же статья не сохранялась дважды: хост в нижнем регистре без
+// порта по умолчанию, отсортированные параметры без параметров
+// отслеживания, без фрагмента. Фрагменты маршрутизации "#!" и "#/"
+// сохраняются. Схема и завершающий слеш меняются только по правилам
+// источника. Некорректный адрес возвращается без изменений.
+func NormalizeURL(rawURL string, rules config.URLRules) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || len(u.Host) == 0 {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); len(port) > 0 && port != defaultPorts[u.Scheme] {
+		host += ":" + port
+	}
+	u.Host = host
+
+	if u.Scheme == "http" && rules.ForceHTTPS && u.Port() == "" {
+		u.Scheme = "https"
+	}
+
+	if rules.StripTrailingSlash && len(u.Path) > 1 && strings.HasSuffix(u.Path, "/") {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	}
+
+	if len(u.Path) == 0 {
+		u.Path = "/"
+	}
+
+	u.RawQuery = normalizeQuery(u.RawQuery, rules)
+	u.ForceQuery = false
+
+	if !strings.HasPrefix(u.Fragment, "!") && !strings.HasPrefix(u.Fragment, "/") {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	return u.String()
+}
+
+// normalizeQuery удаляет лишние параметры и сортирует оставшиеся по имени.
+// Значения параметров не перекодируются.
+func normalizeQuery(rawQuery string, rules config.URLRules) string {
+	type param struct {
+		name string
+		raw  string
+	}
+
+	params := make([]param, 0)
+
+	for _, raw := range strings.Split(rawQuery, "&") {
+		if len(raw) == 0 {
+			continue
+		}
+
+		name, _, _ := strings.Cut(raw, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+
+		if keepParam(name, rules) {
+			params = append(params, param{name: name, raw: raw})
+		}
+	}
+
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].name < params[j].name
+	})
+
+	raws := make([]string, 0, len(params))
+	for _, p := range params {
+		raws = append(raws, p.raw)
+	}
+
+	return strings.Join(raws, "&")
+}
+
+func keepParam(name string, rules config.URLRules) bool {
+	if matchParam(name, rules.Drop) {
+		return false
+	}
+
+	if len(rules.Keep) > 0 {
+		return matchParam(name, rules.Keep)
+	}
+
+	return !matchParam(name, trackingParams)
+}
+
+func matchParam(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+			continue
+		}
+
+		if name == pattern {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,99 @@
+package parsing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		url   string
+		rules config.URLRules
+		want  string
+	}{
+		{
+			name: "tracking params",
+			url:  "https://dropbox.tech/infrastructure/post?utm_source=feed&utm_medium=rss&fbclid=abc",
+			want: "https://dropbox.tech/infrastructure/post",
+		},
+		{
+			name: "params are sorted and kept as is",
+			url:  "https://example.com/post?b=2&utm_campaign=x&a=%D0%BF%D1%80%D0%B8",
+			want: "https://example.com/post?a=%D0%BF%D1%80%D0%B8&b=2",
+		},
+		{
+			name: "host case and default port",
+			url:  "https://Engineering.FB.com:443/2023/12/post/",
+			want: "https://engineering.fb.com/2023/12/post/",
+		},
+		{
+			name: "custom port is kept",
+			url:  "http://example.com:8080/post",
+			want: "http://example.com:8080/post",
+		},
+		{
+			name: "fragment",
+			url:  "https://example.com/post#comments",
+			want: "https://example.com/post",
+		},
+		{
+			name: "hash routing fragment",
+			url:  "https://example.com/#!/posts/1",
+			want: "https://example.com/#!/posts/1",
+		},
+		{
+			name: "empty path",
+			url:  "https://example.com?utm_source=x",
+			want: "https://example.com/",
+		},
+		{
+			name:  "strip trailing slash and force https",
+			url:   "http://example.com/blog/post/",
+			rules: config.URLRules{StripTrailingSlash: true, ForceHTTPS: true},
+			want:  "https://example.com/blog/post",
+		},
+		{
+			name:  "keep list",
+			url:   "https://example.com/index.php?id=10&page=2&session=abc",
+			rules: config.URLRules{Keep: []string{"id"}},
+			want:  "https://example.com/index.php?id=10",
+		},
+		{
+			name:  "keep tracking param explicitly",
+			url:   "https://example.com/post?utm_source=x&id=1",
+			rules: config.URLRules{Keep: []string{"id", "utm_source"}},
+			want:  "https://example.com/post?id=1&utm_source=x",
+		},
+		{
+			name:  "drop list with prefix",
+			url:   "https://aws.amazon.com/blogs/post?sc_channel=sm&sc_campaign=x&lang=ru",
+			rules: config.URLRules{Drop: []string{"sc_*"}},
+			want:  "https://aws.amazon.com/blogs/post?lang=ru",
+		},
+		{
+			name: "not an absolute url",
+			url:  "/relative/path?utm_source=x",
+			want: "/relative/path?utm_source=x",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got := NormalizeURL(tc.url, tc.rules)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package parsing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const pagePlaceholder = "{page}"
+
+// HasPagination сообщает, что у источника настроена постраничная навигация.
+func (s Source) HasPagination() bool {
+	return len(strings.TrimSpace(s.NextSelector)) > 0 || len(strings.TrimSpace(s.PageURLTemplate)) > 0
+}
+
+// NextPageURL возвращает адрес страницы, следующей за страницей page
+// (нумерация с единицы), или пустую строку, если следующей страницы нет.
+// Ссылка на следующую страницу имеет приоритет перед шаблоном адреса.
+func (p *Parser) NextPageURL(source Source, body string, page int) (string, error) {
+	if len(strings.TrimSpace(source.NextSelector)) == 0 {
+		return pageURL(source.PageURLTemplate, page+1), nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("parser: unable to parse %s: %v", source.String(), err)
+	}
+
+	selectors, err := compileSelectors(source)
+	if err != nil {
+		return "", fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
+
+	baseURL, err := documentBaseURL(source.URL, doc)
+	if err != nil {
+		return "", fmt.Errorf("parser: invalid url %s: %v", source.String(), err)
+	}
+
+	next := strings.TrimSpace(selectors.next.value(doc.Selection, selectionAttr("href")))
+	if len(next) == 0 {
+		return pageURL(source.PageURLTemplate, page+1), nil
+	}
+
+	nextURL, err := resolveLink(baseURL, next)
+	if err != nil {
+		return "", fmt.Errorf("parser: %s invalid next page url: %v", source.String(), err)
+	}
+
+	// Ссылка на текущую страницу означает, что страницы закончились.
+	if nextURL == baseURL.String() || nextURL == source.URL {
+		return "", nil
+	}
+
+	return nextURL, nil
+}
+
+func pageURL(template string, page int) string {
+	template = strings.TrimSpace(template)
+	if len(template) == 0 || !strings.Contains(template, pagePlaceholder) {
+		return ""
+	}
+	return strings.ReplaceAll(template, pagePlaceholder, strconv.Itoa(page))
+}
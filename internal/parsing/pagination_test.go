@@ -0,0 +1,132 @@
+package parsing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestParserNextPageURL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		source Source
+		body   string
+		page   int
+		want   string
+	}{
+		{
+			name: "next link",
+			source: Source{
+				URL:          "https://habr.com/ru/hub/go/",
+				NextSelector: "a#pagination-next-page",
+			},
+			body: `<html><body>
+<a id="pagination-next-page" href="/ru/hub/go/page2/">Next</a>
+</body></html>`,
+			page: 1,
+			want: "https://habr.com/ru/hub/go/page2/",
+		},
+		{
+			name: "next link attribute",
+			source: Source{
+				URL:          "https://aws.amazon.com/ru/blogs/architecture/",
+				NextSelector: "div.pagination@data-next",
+			},
+			body: `<html><body>
+<div class="pagination" data-next="page/2/"></div>
+</body></html>`,
+			page: 1,
+			want: "https://aws.amazon.com/ru/blogs/architecture/page/2/",
+		},
+		{
+			name: "next link xpath",
+			source: Source{
+				URL:          "https://example.com/blog/page/2/",
+				SelectorType: config.SelectorXPath,
+				NextSelector: "//link[@rel='next']/@href",
+			},
+			body: `<html><head>
+<link rel="next" href="https://example.com/blog/page/3/">
+</head><body></body></html>`,
+			page: 2,
+			want: "https://example.com/blog/page/3/",
+		},
+		{
+			name: "last page",
+			source: Source{
+				URL:          "https://habr.com/ru/hub/go/page5/",
+				NextSelector: "a#pagination-next-page",
+			},
+			body: `<html><body><span>Last page</span></body></html>`,
+			page: 5,
+			want: "",
+		},
+		{
+			name: "link to the same page",
+			source: Source{
+				URL:          "https://example.com/blog/page/5/",
+				NextSelector: "a.next",
+			},
+			body: `<html><body><a class="next" href="/blog/page/5/">Next</a></body></html>`,
+			page: 5,
+			want: "",
+		},
+		{
+			name: "url template",
+			source: Source{
+				URL:             "https://habr.com/ru/hub/go/",
+				PageURLTemplate: "https://habr.com/ru/hub/go/page{page}/",
+			},
+			page: 1,
+			want: "https://habr.com/ru/hub/go/page2/",
+		},
+		{
+			name: "template when next link is missing",
+			source: Source{
+				URL:             "https://example.com/blog/",
+				NextSelector:    "a.next",
+				PageURLTemplate: "https://example.com/blog/?page={page}",
+			},
+			body: `<html><body></body></html>`,
+			page: 3,
+			want: "https://example.com/blog/?page=4",
+		},
+		{
+			name: "no pagination",
+			source: Source{
+				URL: "https://example.com/blog/",
+			},
+			page: 1,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Обязательные селекторы не участвуют в навигации.
+			tc.source.ArticleSelector = "article"
+			tc.source.TitleSelector = "h2"
+			tc.source.DetailSelector = "a"
+			if tc.source.SelectorType == config.SelectorXPath {
+				tc.source.ArticleSelector = "//article"
+			}
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.NextPageURL(tc.source, tc.body, tc.page)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
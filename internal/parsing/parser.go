@@ -6,25 +6,138 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
 )
 
 type Source struct {
-	URL             string
-	Name            string
+	URL  string
+	Name string
+	// Type тип источника, см. config.TypeHTML и другие.
+	Type string
+	// Extract способ извлечения: config.ExtractSelectors или config.ExtractJSONLD.
+	Extract string
+	// SelectorType язык селекторов: config.SelectorCSS, config.SelectorXPath
+	// или config.SelectorJSONPath.
+	SelectorType    string
 	ArticleSelector string
 	TitleSelector   string
 	DetailSelector  string
+	// Необязательные селекторы метаданных карточки статьи.
+	DateSelector    string
+	AuthorSelector  string
+	SummarySelector string
+	ImageSelector   string
+	// Подсказки для разбора дат карточек.
+	DateFormat string
+	DateLocale string
+	// Постраничная навигация.
+	NextSelector    string
+	PageURLTemplate string
+	// Sitemap настройки источника config.TypeSitemap.
+	Sitemap config.SitemapRules
+	// Watch настройки источника config.TypeWatch.
+	Watch config.WatchRules
+	// URLRules правила нормализации адресов статей.
+	URLRules config.URLRules
+	// Rewrite правила переписывания заголовков и адресов статей.
+	Rewrite config.RewriteRules
+}
+
+// NewSource создаёт источник из конфигурации.
+// Для источников config.TypeJSON и источников с перехватом ответов
+// селекторы всегда являются выражениями JSONPath.
+func NewSource(name, url string, conf config.SourceConfig) Source {
+	selectorType := conf.SelectorType
+	if conf.Type == config.TypeJSON || len(conf.Capture) > 0 {
+		selectorType = config.SelectorJSONPath
+	}
+
+	return Source{
+		URL:             url,
+		Name:            name,
+		Type:            conf.Type,
+		Extract:         conf.Extract,
+		SelectorType:    selectorType,
+		ArticleSelector: conf.ArticleSelector,
+		TitleSelector:   conf.TitleSelector,
+		DetailSelector:  conf.DetailSelector,
+		DateSelector:    conf.DateSelector,
+		AuthorSelector:  conf.AuthorSelector,
+		SummarySelector: conf.SummarySelector,
+		ImageSelector:   conf.ImageSelector,
+		DateFormat:      conf.DateFormat,
+		DateLocale:      conf.DateLocale,
+		NextSelector:    conf.NextSelector,
+		PageURLTemplate: conf.PageURLTemplate,
+		Sitemap:         conf.Sitemap,
+		Watch:           conf.Watch,
+		URLRules:        conf.URLRules,
+		Rewrite:         conf.Rewrite,
+	}
 }
 
 func (s Source) String() string {
 	return fmt.Sprintf("Source(name=%s, url=%s)", s.Name, s.URL)
 }
 
+// Validate проверяет способ извлечения, селекторы и шаблон адреса страниц источника.
+func (s Source) Validate() error {
+	switch s.Extract {
+	case "", config.ExtractSelectors, config.ExtractJSONLD:
+	default:
+		return fmt.Errorf("unknown extract mode %q", s.Extract)
+	}
+
+	if err := s.ValidateRewrite(); err != nil {
+		return err
+	}
+
+	if _, err := dateLocalesFor(s.DateLocale); err != nil {
+		return err
+	}
+
+	if s.Type == config.TypeWatch {
+		_, err := compileWatchSelectors(s)
+		return err
+	}
+
+	if s.Type == config.TypeSitemap {
+		_, err := compileSitemapPattern(s.Sitemap.Pattern)
+		return err
+	}
+
+	if s.SelectorType == config.SelectorJSONPath {
+		_, err := compileJSONPaths(s)
+		return err
+	}
+
+	if _, err := compileSelectors(s); err != nil {
+		return err
+	}
+
+	if len(s.PageURLTemplate) > 0 && !strings.Contains(s.PageURLTemplate, pagePlaceholder) {
+		return fmt.Errorf("page url template %q: missing %s placeholder", s.PageURLTemplate, pagePlaceholder)
+	}
+
+	return nil
+}
+
 type Article struct {
 	Title     string
 	DetailURL string
+	// Published дата публикации в UTC, нулевая, если неизвестна.
+	Published time.Time
+	Author    string
+	Summary   string
+	ImageURL  string
+	// CanonicalURL адрес из <link rel="canonical"> страницы статьи.
+	CanonicalURL string
+	// Enriched статья уже дополнена метаданными своей страницы.
+	Enriched bool
 }
 
 func (a Article) String() string {
@@ -32,63 +145,97 @@ func (a Article) String() string {
 }
 
 type Parser struct {
+	// now текущее время, подменяется в тестах.
+	now func() time.Time
 }
 
 func NewParser() *Parser {
-	return &Parser{}
+	return &Parser{now: time.Now}
 }
 
-func (p *Parser) Parse(source Source, body string) ([]Article, error) {
+// Parse извлекает статьи из страницы списка. Отклонённые карточки
+// не являются ошибкой и перечисляются в результате.
+func (p *Parser) Parse(source Source, body string) (ParseResult, error) {
+	var result ParseResult
+
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("parser: unable to parse %s: %v", source.String(), err)
+		return result, fmt.Errorf("parser: unable to parse %s: %v", source.String(), err)
 	}
 
 	log.Printf("parser: %s parsing succeeded", source.String())
 
-	articles := make([]Article, 0, 10)
-
-	doc.Find(source.ArticleSelector).Each(func(i int, articleCard *goquery.Selection) {
-		log.Printf("parser: %s parsing article %d", source.String(), i)
+	selectors, err := compileSelectors(source)
+	if err != nil {
+		return result, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
 
-		title := articleCard.Find(source.TitleSelector).Text()
-		title = formatTitle(title)
+	rewrites, err := compileRewrites(source)
+	if err != nil {
+		return result, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
 
-		detailURL, _ := articleCard.Find(source.DetailSelector).Attr("href")
-		detailURL = strings.TrimSpace(detailURL)
+	baseURL, err := documentBaseURL(source.URL, doc)
+	if err != nil {
+		return result, fmt.Errorf("parser: invalid url %s: %v", source.String(), err)
+	}
 
-		if len(title) == 0 {
-			log.Printf("parser: %s article %d parsing failed - empty title", source.String(), i)
-			return
+	if source.Extract == config.ExtractJSONLD {
+		result = parseStructuredData(source, rewrites, baseURL, doc)
+		if len(result.Articles) > 0 || selectors.article == nil {
+			return result, nil
 		}
 
-		if len(detailURL) == 0 {
-			log.Printf("parser: %s article %d parsing failed - empty detail url", source.String(), i)
-			return
-		}
+		log.Printf("parser: %s no structured data found, falling back to selectors", source.String())
+	}
 
-		if isLinkRelative(detailURL) {
-			u, err := url.Parse(source.URL)
-			if err != nil {
-				return
-			}
+	result.Articles = make([]Article, 0, 10)
 
-			detailURL = strings.TrimPrefix(detailURL, ".")
-			detailURL = strings.TrimPrefix(detailURL, "/")
-			detailURL = fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, detailURL)
-		}
+	dates := newDateParser(source, p.now())
+
+	cards := selectors.article.find(doc.Selection)
+	result.Matched = cards.Length()
 
-		article := Article{
-			Title:     title,
-			DetailURL: detailURL,
+	cards.Each(func(i int, articleCard *goquery.Selection) {
+		title := selectors.title.value(articleCard, selectionText)
+		detailURL := selectors.detail.value(articleCard, selectionAttr("href"))
+
+		article, ok := result.newArticle(i, source, rewrites, baseURL, title, detailURL)
+		if !ok {
+			return
 		}
 
-		articles = append(articles, article)
+		parseCardMetadata(selectors, dates, baseURL, articleCard, &article)
 
-		log.Printf("parser: %s parsing %s", source.String(), article.String())
+		result.Articles = append(result.Articles, article)
 	})
 
-	return articles, nil
+	return result, nil
+}
+
+// parseCardMetadata заполняет необязательные поля статьи. Отсутствие
+// метаданных не является ошибкой - карточка всё равно принимается.
+func parseCardMetadata(selectors sourceSelectors, dates dateParser, baseURL *url.URL, card *goquery.Selection, article *Article) {
+	if selectors.date != nil {
+		article.Published = dates.parse(selectors.date.value(card, selectionDate))
+	}
+
+	if selectors.author != nil {
+		article.Author = formatTitle(selectors.author.value(card, selectionFirstText))
+	}
+
+	if selectors.summary != nil {
+		article.Summary = formatTitle(selectors.summary.value(card, selectionFirstText))
+	}
+
+	if selectors.image != nil {
+		src := strings.TrimSpace(selectors.image.value(card, selectionImage))
+		if len(src) > 0 {
+			if imageURL, err := resolveLink(baseURL, src); err == nil {
+				article.ImageURL = imageURL
+			}
+		}
+	}
 }
 
 var regexpWhitespace = regexp.MustCompile(`\s+`)
@@ -99,11 +246,3 @@ func formatTitle(s string) string {
 	s = regexpWhitespace.ReplaceAllLiteralString(s, " ")
 	return s
 }
-
-func isLinkRelative(detailURL string) bool {
-	if strings.HasPrefix(detailURL, "http://") ||
-		strings.HasPrefix(detailURL, "https://") {
-		return false
-	}
-	return true
-}
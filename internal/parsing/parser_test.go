@@ -2,8 +2,11 @@ package parsing
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
 )
 
 func TestParser(t *testing.T) {
@@ -35,27 +38,7 @@ func TestParser(t *testing.T) {
 					DetailURL: "https://research.swtch.com/acmscored",
 				},
 			},
-			body: `<html>
-		  <body>
-		    <a class="rss" href="/feed.atom">RSS</a>
-		    <div class="main">
-		    <ul class="toc">
-		      <!-- Будет пропущено - нет названия по селектору -->
-		      <li class="toc-head"><b>Table of Contents</b> (favorites in bold)</li>
-		      <li><a href="testing" class="">Go Testing By Example</a> <span class="toc-when">December 2023</span>
-		        <div class="toc-summary">
-		        The importance of testing, and twenty tips for writing good tests.
-		        </div>
-		      </li>
-		      <li><a href="acmscored" class="">Open Source Supply Chain Security at Google</a> <span class="toc-when">November 2023</span>
-		        <div class="toc-summary">
-		        A remote talk at ACM SCORED 2023
-		        </div>
-		      </li>
-		    </ul>
-		    </div>
-		  </body>
-		</html>`,
+			body: bodyResearchSwtch,
 		},
 		{
 			name: "https://eli.thegreenplace.net/tag/go",
@@ -80,7 +63,300 @@ func TestParser(t *testing.T) {
 					DetailURL: "https://eli.thegreenplace.net/2023/better-http-server-routing-in-go-122/",
 				},
 			},
-			body: `<!DOCTYPE html>
+			body: bodyEliThegreenplace,
+		},
+		{
+			name: "https://threedots.tech/",
+			source: Source{
+				URL:             "https://threedots.tech/",
+				Name:            "https://threedots.tech/",
+				ArticleSelector: "article.post-entry",
+				TitleSelector:   "header.post-header > h3.post-title a",
+				DetailSelector:  "header.post-header > h3.post-title a",
+			},
+			want: []Article{
+				{
+					Title:     "Making Games in Go for Absolute Beginners",
+					DetailURL: "https://threedots.tech/post/making-games-in-go/",
+				},
+				{
+					Title:     "Watermill 1.3 released, an open-source event-driven Go library",
+					DetailURL: "https://threedots.tech/post/watermill-1-3/",
+				},
+			},
+			body: bodyThreeDots,
+		},
+		{
+			name: "base href",
+			source: Source{
+				Name:            "https://example.com/blog/",
+				URL:             "https://example.com/blog/",
+				ArticleSelector: "article",
+				TitleSelector:   "h2",
+				DetailSelector:  "a",
+			},
+			want: []Article{
+				{
+					Title:     "Relative to base",
+					DetailURL: "https://example.com/posts/relative",
+				},
+				{
+					Title:     "Parent of base",
+					DetailURL: "https://example.com/parent",
+				},
+				{
+					Title:     "Protocol relative",
+					DetailURL: "https://cdn.example.com/post",
+				},
+			},
+			body: `<html>
+<head>
+  <base href="/posts/">
+</head>
+<body>
+  <article><h2>Relative to base</h2><a href="relative">Read</a></article>
+  <article><h2>Parent of base</h2><a href="../parent">Read</a></article>
+  <article><h2>Protocol relative</h2><a href="//cdn.example.com/post">Read</a></article>
+  <!-- Будет пропущено - ссылка не на http(s)-ресурс -->
+  <article><h2>Mail</h2><a href="mailto:author@example.com">Mail</a></article>
+</body>
+</html>`,
+		},
+		{
+			name: "card metadata",
+			source: Source{
+				Name:            "https://example.com/blog/",
+				URL:             "https://example.com/blog/",
+				ArticleSelector: "article",
+				TitleSelector:   "h2",
+				DetailSelector:  "a.link",
+				DateSelector:    "time",
+				AuthorSelector:  ".author",
+				SummarySelector: "p.summary",
+				ImageSelector:   "img.cover",
+			},
+			want: []Article{
+				{
+					Title:     "With metadata",
+					DetailURL: "https://example.com/blog/with-metadata",
+					Published: time.Date(2023, 12, 11, 9, 30, 0, 0, time.UTC),
+					Author:    "Jane Doe",
+					Summary:   "Short description of the post.",
+					ImageURL:  "https://example.com/images/cover.png",
+				},
+				{
+					// Дата из текста, картинка с отложенной загрузкой.
+					Title:     "Lazy image",
+					DetailURL: "https://example.com/blog/lazy-image",
+					Published: time.Date(2023, 11, 24, 0, 0, 0, 0, time.UTC),
+					ImageURL:  "https://example.com/blog/lazy.png",
+				},
+				{
+					// Метаданные необязательны.
+					Title:     "Without metadata",
+					DetailURL: "https://example.com/blog/without-metadata",
+				},
+			},
+			body: `<html>
+<body>
+  <article>
+    <h2>With metadata</h2>
+    <a class="link" href="with-metadata">Read</a>
+    <time datetime="2023-12-11T12:30:00+03:00">11 December</time>
+    <span class="author"> Jane
+      Doe </span>
+    <p class="summary">Short description
+      of the post.</p>
+    <img class="cover" src="/images/cover.png">
+  </article>
+  <article>
+    <h2>Lazy image</h2>
+    <a class="link" href="lazy-image">Read</a>
+    <time>Nov 24, 2023</time>
+    <img class="cover" src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" data-src="lazy.png">
+  </article>
+  <article>
+    <h2>Without metadata</h2>
+    <a class="link" href="without-metadata">Read</a>
+  </article>
+</body>
+</html>`,
+		},
+		{
+			name: "selector expressions",
+			source: Source{
+				Name:            "https://example.com/",
+				URL:             "https://example.com/",
+				ArticleSelector: "div.card",
+				TitleSelector:   "@aria-label",
+				DetailSelector:  "@data-href",
+				DateSelector:    "span.date::text",
+				ImageSelector:   "div.cover@data-background",
+			},
+			want: []Article{
+				{
+					Title:     "Title from attribute",
+					DetailURL: "https://example.com/posts/1",
+					Published: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+					ImageURL:  "https://example.com/covers/1.png",
+				},
+			},
+			body: `<html>
+<body>
+  <div class="card" aria-label="Title from attribute" data-href="/posts/1">
+    <span class="date">2023-12-01</span>
+    <div class="cover" data-background="/covers/1.png"></div>
+  </div>
+</body>
+</html>`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.Parse(tc.source, tc.body)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.Articles)
+		})
+	}
+}
+
+func TestParserXPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		source Source
+		body   string
+		want   []Article
+	}{
+		{
+			name: "https://research.swtch.com/",
+			source: Source{
+				Name:            "https://research.swtch.com/",
+				URL:             "https://research.swtch.com/",
+				SelectorType:    config.SelectorXPath,
+				ArticleSelector: "//ul[@class='toc']/li",
+				TitleSelector:   "a",
+				DetailSelector:  "a/@href",
+			},
+			want: []Article{
+				{
+					Title:     "Go Testing By Example",
+					DetailURL: "https://research.swtch.com/testing",
+				},
+				{
+					Title:     "Open Source Supply Chain Security at Google",
+					DetailURL: "https://research.swtch.com/acmscored",
+				},
+			},
+			body: bodyResearchSwtch,
+		},
+		{
+			// Дата находится в соседней ячейке, что в CSS не выразить.
+			name: "https://eli.thegreenplace.net/tag/go",
+			source: Source{
+				Name:            "https://eli.thegreenplace.net/tag/go",
+				URL:             "https://eli.thegreenplace.net/tag/go",
+				SelectorType:    config.SelectorXPath,
+				ArticleSelector: "//table[@class='archive-list']//tr",
+				TitleSelector:   "td/a",
+				DetailSelector:  "td/a",
+				DateSelector:    "substring-before(td[1], ':')",
+			},
+			want: []Article{
+				{
+					Title:     "Using Ollama with LangChainGo",
+					DetailURL: "https://eli.thegreenplace.net/2023/using-ollama-with-langchaingo/",
+					Published: time.Date(2023, 11, 22, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Title:     "Retrieval Augmented Generation in Go",
+					DetailURL: "https://eli.thegreenplace.net/2023/retrieval-augmented-generation-in-go/",
+					Published: time.Date(2023, 11, 10, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Title:     "Better HTTP server routing in Go 1.22",
+					DetailURL: "https://eli.thegreenplace.net/2023/better-http-server-routing-in-go-122/",
+					Published: time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			body: bodyEliThegreenplace,
+		},
+		{
+			name: "https://threedots.tech/",
+			source: Source{
+				URL:             "https://threedots.tech/",
+				Name:            "https://threedots.tech/",
+				SelectorType:    config.SelectorXPath,
+				ArticleSelector: "//article[contains(@class, 'post-entry')]",
+				TitleSelector:   "header/h3[@class='post-title']/a::text",
+				DetailSelector:  "header/h3[@class='post-title']/a",
+			},
+			want: []Article{
+				{
+					Title:     "Making Games in Go for Absolute Beginners",
+					DetailURL: "https://threedots.tech/post/making-games-in-go/",
+				},
+				{
+					Title:     "Watermill 1.3 released, an open-source event-driven Go library",
+					DetailURL: "https://threedots.tech/post/watermill-1-3/",
+				},
+			},
+			body: bodyThreeDots,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.Parse(tc.source, tc.body)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.Articles)
+		})
+	}
+}
+
+const bodyResearchSwtch = `<html>
+		  <body>
+		    <a class="rss" href="/feed.atom">RSS</a>
+		    <div class="main">
+		    <ul class="toc">
+		      <!-- Будет пропущено - нет названия по селектору -->
+		      <li class="toc-head"><b>Table of Contents</b> (favorites in bold)</li>
+		      <li><a href="testing" class="">Go Testing By Example</a> <span class="toc-when">December 2023</span>
+		        <div class="toc-summary">
+		        The importance of testing, and twenty tips for writing good tests.
+		        </div>
+		      </li>
+		      <li><a href="acmscored" class="">Open Source Supply Chain Security at Google</a> <span class="toc-when">November 2023</span>
+		        <div class="toc-summary">
+		        A remote talk at ACM SCORED 2023
+		        </div>
+		      </li>
+		    </ul>
+		    </div>
+		  </body>
+		</html>`
+
+const bodyEliThegreenplace = `<!DOCTYPE html>
 <html>
 <head>
     <title>Articles in tag "Go"</title>
@@ -112,28 +388,9 @@ func TestParser(t *testing.T) {
 </div>
 </body>
 </html>
-`,
-		},
-		{
-			name: "https://threedots.tech/",
-			source: Source{
-				URL:             "https://threedots.tech/",
-				Name:            "https://threedots.tech/",
-				ArticleSelector: "article.post-entry",
-				TitleSelector:   "header.post-header > h3.post-title a",
-				DetailSelector:  "header.post-header > h3.post-title a",
-			},
-			want: []Article{
-				{
-					Title:     "Making Games in Go for Absolute Beginners",
-					DetailURL: "https://threedots.tech/post/making-games-in-go/",
-				},
-				{
-					Title:     "Watermill 1.3 released, an open-source event-driven Go library",
-					DetailURL: "https://threedots.tech/post/watermill-1-3/",
-				},
-			},
-			body: `<html lang=en>
+`
+
+const bodyThreeDots = `<html lang=en>
 <body>
 <section class="main post-list">
     <article class=post-entry>
@@ -186,7 +443,49 @@ func TestParser(t *testing.T) {
     </article>
 </section>
 </body>
-</html>`,
+</html>`
+
+func TestParserParseResult(t *testing.T) {
+	t.Parallel()
+
+	source := Source{
+		URL:             "https://example.com/blog/",
+		ArticleSelector: "article",
+		TitleSelector:   "h2",
+		DetailSelector:  "a",
+	}
+
+	cases := []struct {
+		name string
+		body string
+		want ParseResult
+	}{
+		{
+			name: "rejected cards",
+			body: `<html><body>
+<article><h2>First post</h2><a href="/blog/first">Read</a></article>
+<article><h2></h2><a href="/blog/untitled">Read</a></article>
+<article><h2>No link</h2></article>
+<article><h2>Mail</h2><a href="mailto:blog@example.com">Write</a></article>
+</body></html>`,
+			want: ParseResult{
+				Matched: 4,
+				Articles: []Article{
+					{Title: "First post", DetailURL: "https://example.com/blog/first"},
+				},
+				Rejected: []Rejection{
+					{Card: 1, Reason: RejectEmptyTitle},
+					{Card: 2, Reason: RejectEmptyDetailURL},
+					{Card: 3, Reason: RejectInvalidDetailURL, Detail: `unsupported scheme: "mailto"`},
+				},
+			},
+		},
+		{
+			name: "no cards matched",
+			body: `<html><body><div class="post"><h2>Redesigned</h2></div></body></html>`,
+			want: ParseResult{
+				Articles: []Article{},
+			},
 		},
 	}
 
@@ -199,11 +498,29 @@ func TestParser(t *testing.T) {
 			parser := NewParser()
 
 			// act
-			got, err := parser.Parse(tc.source, tc.body)
+			got, err := parser.Parse(source, tc.body)
 
 			// assert
 			require.NoError(t, err)
 			require.Equal(t, tc.want, got)
 		})
 	}
+
+	t.Run("rejection summary", func(t *testing.T) {
+		t.Parallel()
+
+		result := ParseResult{
+			Rejected: []Rejection{
+				{Card: 0, Reason: RejectEmptyTitle},
+				{Card: 2, Reason: RejectEmptyDetailURL},
+				{Card: 5, Reason: RejectEmptyTitle},
+			},
+		}
+
+		// act
+		got := result.RejectionSummary()
+
+		// assert
+		require.Equal(t, "empty detail url: 1, empty title: 2", got)
+	})
 }
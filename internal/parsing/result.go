@@ -0,0 +1,101 @@
+package parsing
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Причины, по которым карточка не стала статьёй.
+const (
+	RejectEmptyTitle       = "empty title"
+	RejectEmptyDetailURL   = "empty detail url"
+	RejectInvalidDetailURL = "invalid detail url"
+)
+
+// Rejection карточка, из которой не удалось получить статью.
+type Rejection struct {
+	// Card номер карточки на странице, начиная с нуля.
+	Card   int
+	Reason string
+	// Detail подробности, например ошибка разбора адреса.
+	Detail string
+}
+
+// ParseResult результат разбора страницы: сколько карточек нашлось,
+// какие из них стали статьями и почему были отклонены остальные.
+// Ноль найденных карточек означает сломанный селектор, а не тихий блог.
+type ParseResult struct {
+	Matched  int
+	Articles []Article
+	Rejected []Rejection
+}
+
+// newArticle проверяет заголовок и адрес карточки и создаёт статью
+// с переписанным, разрешённым и нормализованным адресом. Если карточка
+// не подходит, причина сохраняется в результате.
+func (r *ParseResult) newArticle(
+	card int,
+	source Source,
+	rewrites rewriteRules,
+	baseURL *url.URL,
+	title, detailURL string,
+) (Article, bool) {
+	title = rewrites.rewriteTitle(formatTitle(title))
+	detailURL = strings.TrimSpace(detailURL)
+
+	if len(title) == 0 {
+		r.reject(card, RejectEmptyTitle, "")
+		return Article{}, false
+	}
+
+	if len(detailURL) == 0 {
+		r.reject(card, RejectEmptyDetailURL, "")
+		return Article{}, false
+	}
+
+	resolved, err := resolveLink(baseURL, detailURL)
+	if err != nil {
+		r.reject(card, RejectInvalidDetailURL, err.Error())
+		return Article{}, false
+	}
+
+	// Переписанный адрес проверяется заново: правило могло
+	// вернуть относительный адрес или адрес с другой схемой.
+	if resolved, err = resolveLink(baseURL, rewrites.rewriteURL(resolved)); err != nil {
+		r.reject(card, RejectInvalidDetailURL, err.Error())
+		return Article{}, false
+	}
+
+	return Article{
+		Title:     title,
+		DetailURL: NormalizeURL(resolved, source.URLRules),
+	}, true
+}
+
+func (r *ParseResult) reject(card int, reason, detail string) {
+	r.Rejected = append(r.Rejected, Rejection{Card: card, Reason: reason, Detail: detail})
+}
+
+// RejectionSummary сводка причин отклонения карточек,
+// например "empty detail url: 1, empty title: 2".
+func (r ParseResult) RejectionSummary() string {
+	counts := make(map[string]int)
+	for _, rejection := range r.Rejected {
+		counts[rejection.Reason]++
+	}
+
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		parts = append(parts, fmt.Sprintf("%s: %d", reason, counts[reason]))
+	}
+
+	return strings.Join(parts, ", ")
+}
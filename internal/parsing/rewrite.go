@@ -0,0 +1,145 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+var errInvalidRewrite = errors.New("invalid rewrite rule")
+
+// rewriteRule скомпилированное правило переписывания.
+type rewriteRule struct {
+	pattern *regexp.Regexp
+	replace string
+	unwrap  string
+}
+
+// rewriteRules скомпилированные правила переписывания источника.
+type rewriteRules struct {
+	title []rewriteRule
+	url   []rewriteRule
+}
+
+func compileRewrites(source Source) (rewriteRules, error) {
+	var (
+		rules rewriteRules
+		err   error
+	)
+
+	if rules.title, err = compileRewriteRules("title", source.Rewrite.Title, false); err != nil {
+		return rules, err
+	}
+
+	if rules.url, err = compileRewriteRules("url", source.Rewrite.URL, true); err != nil {
+		return rules, err
+	}
+
+	return rules, nil
+}
+
+func compileRewriteRules(field string, rules []config.RewriteRule, allowUnwrap bool) ([]rewriteRule, error) {
+	compiled := make([]rewriteRule, 0, len(rules))
+
+	for i, rule := range rules {
+		unwrap := strings.TrimSpace(rule.Unwrap)
+
+		if len(unwrap) > 0 && !allowUnwrap {
+			return nil, fmt.Errorf("%s rewrite %d: %w: unwrap is supported for urls only", field, i, errInvalidRewrite)
+		}
+
+		if len(unwrap) == 0 && len(rule.Pattern) == 0 {
+			return nil, fmt.Errorf("%s rewrite %d: %w: pattern or unwrap is required", field, i, errInvalidRewrite)
+		}
+
+		var pattern *regexp.Regexp
+		if len(rule.Pattern) > 0 {
+			var err error
+			if pattern, err = regexp.Compile(rule.Pattern); err != nil {
+				return nil, fmt.Errorf("%s rewrite %d: %w %q: %v", field, i, errInvalidRewrite, rule.Pattern, err)
+			}
+		}
+
+		compiled = append(compiled, rewriteRule{pattern: pattern, replace: rule.Replace, unwrap: unwrap})
+	}
+
+	return compiled, nil
+}
+
+// rewriteTitle применяет правила к заголовку и заново форматирует его:
+// после удаления префикса или суффикса остаются лишние пробелы.
+func (r rewriteRules) rewriteTitle(title string) string {
+	if len(r.title) == 0 {
+		return title
+	}
+
+	for _, rule := range r.title {
+		title = rule.pattern.ReplaceAllString(title, rule.replace)
+	}
+
+	return formatTitle(title)
+}
+
+// rewriteURL применяет правила к абсолютному адресу статьи.
+func (r rewriteRules) rewriteURL(detailURL string) string {
+	for _, rule := range r.url {
+		if len(rule.unwrap) == 0 {
+			detailURL = rule.pattern.ReplaceAllString(detailURL, rule.replace)
+			continue
+		}
+
+		if rule.pattern != nil && !rule.pattern.MatchString(detailURL) {
+			continue
+		}
+
+		detailURL = unwrapURL(detailURL, rule.unwrap)
+	}
+
+	return detailURL
+}
+
+// unwrapURL извлекает настоящий адрес статьи из параметра адреса-редиректора,
+// например https://example.com/away?url=https%3A%2F%2Fgo.dev%2Fblog.
+// Относительный адрес разрешается относительно редиректора. Если параметра
+// нет, адрес возвращается без изменений.
+func unwrapURL(redirectURL, param string) string {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return redirectURL
+	}
+
+	target := strings.TrimSpace(u.Query().Get(param))
+	if len(target) == 0 {
+		return redirectURL
+	}
+
+	ref, err := url.Parse(target)
+	if err != nil {
+		return redirectURL
+	}
+
+	return u.ResolveReference(ref).String()
+}
+
+// ValidateRewrite проверяет правила переписывания источника.
+// Для лент это единственная проверка: селекторы у них не используются.
+func (s Source) ValidateRewrite() error {
+	_, err := compileRewrites(s)
+	return err
+}
+
+// RewriteTitle применяет правила источника к заголовку, полученному
+// не со страницы списка, например со страницы самой статьи. Если правила
+// некорректны, заголовок возвращается без изменений: их проверяет Validate.
+func (s Source) RewriteTitle(title string) string {
+	rewrites, err := compileRewrites(s)
+	if err != nil {
+		return title
+	}
+
+	return rewrites.rewriteTitle(title)
+}
@@ -0,0 +1,213 @@
+package parsing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestRewriteTitle(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		rules []config.RewriteRule
+		title string
+		want  string
+	}{
+		{
+			name:  "no rules",
+			title: "Blog: Scaling Postgres",
+			want:  "Blog: Scaling Postgres",
+		},
+		{
+			name:  "prefix",
+			rules: []config.RewriteRule{{Pattern: `^Blog:`}},
+			title: "Blog: Scaling Postgres",
+			want:  "Scaling Postgres",
+		},
+		{
+			name:  "suffix",
+			rules: []config.RewriteRule{{Pattern: `\s*\|\s*Company Engineering$`}},
+			title: "Scaling Postgres | Company Engineering",
+			want:  "Scaling Postgres",
+		},
+		{
+			name:  "author with groups",
+			rules: []config.RewriteRule{{Pattern: `^(?P<title>.+?)\s+by\s+\S+ \S+$`, Replace: "${title}"}},
+			title: "Scaling Postgres by Jane Doe",
+			want:  "Scaling Postgres",
+		},
+		{
+			name: "rules are applied in order",
+			rules: []config.RewriteRule{
+				{Pattern: `^Blog:`},
+				{Pattern: `^\s*\[(\w+)\]\s*(.+)$`, Replace: "$2 ($1)"},
+			},
+			title: "Blog: [Go] Generics",
+			want:  "Generics (Go)",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rewrites, err := compileRewrites(Source{Rewrite: config.RewriteRules{Title: tc.rules}})
+			require.NoError(t, err)
+
+			// act
+			got := rewrites.rewriteTitle(tc.title)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRewriteURL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		rules []config.RewriteRule
+		url   string
+		want  string
+	}{
+		{
+			name:  "replace",
+			rules: []config.RewriteRule{{Pattern: `^https://m\.habr\.com/`, Replace: "https://habr.com/"}},
+			url:   "https://m.habr.com/ru/articles/1/",
+			want:  "https://habr.com/ru/articles/1/",
+		},
+		{
+			name:  "unwrap",
+			rules: []config.RewriteRule{{Unwrap: "url"}},
+			url:   "https://example.com/away?url=https%3A%2F%2Fgo.dev%2Fblog%2Fgenerics%3Fa%3D1",
+			want:  "https://go.dev/blog/generics?a=1",
+		},
+		{
+			name:  "unwrap relative",
+			rules: []config.RewriteRule{{Unwrap: "to"}},
+			url:   "https://example.com/go?to=/blog/post",
+			want:  "https://example.com/blog/post",
+		},
+		{
+			name:  "unwrap without param",
+			rules: []config.RewriteRule{{Unwrap: "url"}},
+			url:   "https://example.com/blog/post",
+			want:  "https://example.com/blog/post",
+		},
+		{
+			name:  "unwrap restricted by pattern",
+			rules: []config.RewriteRule{{Pattern: `^https://t\.example\.com/`, Unwrap: "u"}},
+			url:   "https://example.com/search?u=https://go.dev/",
+			want:  "https://example.com/search?u=https://go.dev/",
+		},
+		{
+			name: "unwrap then replace",
+			rules: []config.RewriteRule{
+				{Pattern: `^https://t\.example\.com/`, Unwrap: "u"},
+				{Pattern: `^http://`, Replace: "https://"},
+			},
+			url:  "https://t.example.com/r?u=http://go.dev/blog/",
+			want: "https://go.dev/blog/",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rewrites, err := compileRewrites(Source{Rewrite: config.RewriteRules{URL: tc.rules}})
+			require.NoError(t, err)
+
+			// act
+			got := rewrites.rewriteURL(tc.url)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCompileRewrites(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		rules config.RewriteRules
+	}{
+		{
+			name:  "invalid pattern",
+			rules: config.RewriteRules{URL: []config.RewriteRule{{Pattern: `^https://(`}}},
+		},
+		{
+			name:  "empty rule",
+			rules: config.RewriteRules{Title: []config.RewriteRule{{Replace: "x"}}},
+		},
+		{
+			name:  "unwrap for title",
+			rules: config.RewriteRules{Title: []config.RewriteRule{{Unwrap: "url"}}},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			_, err := compileRewrites(Source{Rewrite: tc.rules})
+
+			// assert
+			require.ErrorIs(t, err, errInvalidRewrite)
+		})
+	}
+}
+
+func TestParserParseRewrite(t *testing.T) {
+	t.Parallel()
+
+	source := Source{
+		URL:             "https://example.com/blog/",
+		ArticleSelector: "article",
+		TitleSelector:   "h2",
+		DetailSelector:  "a",
+		Rewrite: config.RewriteRules{
+			Title: []config.RewriteRule{{Pattern: `^Blog:`}},
+			URL:   []config.RewriteRule{{Pattern: `^https://example\.com/away\?`, Unwrap: "url"}},
+		},
+	}
+
+	body := `<html><body>
+<article><h2>Blog: Generics</h2><a href="/away?url=https%3A%2F%2Fgo.dev%2Fblog%2Fgenerics%3Futm_source%3Dx">Read</a></article>
+<article><h2>Blog:</h2><a href="/blog/empty">Read</a></article>
+<article><h2>Blog: Mail</h2><a href="/away?url=mailto:blog@example.com">Write</a></article>
+</body></html>`
+
+	parser := NewParser()
+
+	// act
+	got, err := parser.Parse(source, body)
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, ParseResult{
+		Matched: 3,
+		Articles: []Article{
+			{Title: "Generics", DetailURL: "https://go.dev/blog/generics"},
+		},
+		Rejected: []Rejection{
+			{Card: 1, Reason: RejectEmptyTitle},
+			{Card: 2, Reason: RejectInvalidDetailURL, Detail: `unsupported scheme: "mailto"`},
+		},
+	}, got)
+}
@@ -0,0 +1,297 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+// Способы извлечения значения из найденного элемента.
+type extraction int
+
+const (
+	// extractDefault способ извлечения определяется полем статьи.
+	extractDefault extraction = iota
+	extractText
+	extractHTML
+	extractAttr
+)
+
+const (
+	suffixText = "::text"
+	suffixHTML = "::html"
+)
+
+var (
+	errEmptySelector   = errors.New("empty selector")
+	errInvalidSelector = errors.New("invalid selector")
+)
+
+var (
+	// regexpAttrSuffix атрибут в конце выражения: "a@href", "@data-href".
+	regexpAttrSuffix = regexp.MustCompile(`@([a-zA-Z_:][-a-zA-Z0-9_:.]*)$`)
+	// regexpXPathAttr выражение XPath, выбирающее атрибут: "a/@href".
+	regexpXPathAttr = regexp.MustCompile(`(^|/)(@|attribute::)[-a-zA-Z0-9_:.*]+$`)
+)
+
+// Selector разобранное выражение селектора. Поддерживаемый синтаксис:
+//
+//	h2 a          - CSS-селектор, значение извлекается способом по умолчанию
+//	a@href        - значение атрибута href первого найденного элемента
+//	h2::text      - текст всех найденных элементов
+//	div::html     - внутренний HTML первого найденного элемента
+//	@title        - атрибут самой карточки статьи
+//	::html        - внутренний HTML самой карточки статьи
+//
+// Выражения XPath поддерживают суффиксы ::text и ::html, атрибуты
+// выбираются средствами XPath: "a/@href".
+type Selector struct {
+	expr       string
+	css        string
+	attr       string
+	extraction extraction
+	matcher    goquery.Matcher
+	xpath      *xpath.Expr
+}
+
+// ParseSelector разбирает и компилирует выражение селектора.
+func ParseSelector(expr string) (Selector, error) {
+	s := Selector{expr: expr}
+
+	rest := strings.TrimSpace(expr)
+	if len(rest) == 0 {
+		return s, errEmptySelector
+	}
+
+	switch {
+	case strings.HasSuffix(rest, suffixText):
+		s.extraction = extractText
+		rest = strings.TrimSuffix(rest, suffixText)
+	case strings.HasSuffix(rest, suffixHTML):
+		s.extraction = extractHTML
+		rest = strings.TrimSuffix(rest, suffixHTML)
+	default:
+		if m := regexpAttrSuffix.FindStringSubmatchIndex(rest); m != nil {
+			s.extraction = extractAttr
+			s.attr = rest[m[2]:m[3]]
+			rest = rest[:m[0]]
+		}
+	}
+
+	s.css = strings.TrimSpace(rest)
+	if len(s.css) == 0 {
+		return s, nil
+	}
+
+	matcher, err := cascadia.Compile(s.css)
+	if err != nil {
+		return s, fmt.Errorf("%w %q: %v", errInvalidSelector, expr, err)
+	}
+
+	s.matcher = matcher
+
+	return s, nil
+}
+
+// ParseXPathSelector разбирает и компилирует выражение XPath.
+// Выражение вычисляется относительно карточки статьи.
+func ParseXPathSelector(expr string) (Selector, error) {
+	s := Selector{expr: expr}
+
+	rest := strings.TrimSpace(expr)
+	if len(rest) == 0 {
+		return s, errEmptySelector
+	}
+
+	switch {
+	case strings.HasSuffix(rest, suffixText):
+		s.extraction = extractText
+		rest = strings.TrimSuffix(rest, suffixText)
+	case strings.HasSuffix(rest, suffixHTML):
+		s.extraction = extractHTML
+		rest = strings.TrimSuffix(rest, suffixHTML)
+	}
+
+	rest = strings.TrimSpace(rest)
+
+	// Значение выбранного атрибута доступно как текст узла.
+	if regexpXPathAttr.MatchString(rest) && s.extraction == extractDefault {
+		s.extraction = extractText
+	}
+
+	compiled, err := xpath.Compile(rest)
+	if err != nil {
+		return s, fmt.Errorf("%w %q: %v", errInvalidSelector, expr, err)
+	}
+
+	s.xpath = compiled
+
+	return s, nil
+}
+
+func (s Selector) String() string {
+	return s.expr
+}
+
+// find возвращает элементы, найденные внутри карточки,
+// или саму карточку, если CSS-часть выражения пуста.
+func (s Selector) find(card *goquery.Selection) *goquery.Selection {
+	if s.xpath != nil {
+		var nodes []*html.Node
+		for _, node := range card.Nodes {
+			nodes = append(nodes, htmlquery.QuerySelectorAll(node, s.xpath)...)
+		}
+		// Найденные узлы атрибутов не принадлежат документу,
+		// поэтому выборка собирается из узлов напрямую.
+		return &goquery.Selection{Nodes: nodes}
+	}
+
+	if s.matcher == nil {
+		return card
+	}
+
+	return card.FindMatcher(s.matcher)
+}
+
+// value извлекает значение способом, указанным в выражении,
+// или способом по умолчанию def, если способ не указан.
+func (s Selector) value(card *goquery.Selection, def func(*goquery.Selection) string) string {
+	// Выражения XPath вида "substring-before(td, ':')" возвращают
+	// строку, а не набор узлов.
+	if s.xpath != nil && card.Length() > 0 {
+		result := s.xpath.Evaluate(htmlquery.CreateXPathNavigator(card.Get(0)))
+		if _, ok := result.(*xpath.NodeIterator); !ok {
+			return fmt.Sprint(result)
+		}
+	}
+
+	found := s.find(card)
+
+	switch s.extraction {
+	case extractText:
+		return found.Text()
+	case extractHTML:
+		h, _ := found.Html()
+		return h
+	case extractAttr:
+		return found.AttrOr(s.attr, "")
+	}
+
+	return def(found)
+}
+
+func selectionText(s *goquery.Selection) string {
+	return s.Text()
+}
+
+func selectionFirstText(s *goquery.Selection) string {
+	return s.First().Text()
+}
+
+func selectionAttr(name string) func(*goquery.Selection) string {
+	return func(s *goquery.Selection) string {
+		return s.AttrOr(name, "")
+	}
+}
+
+// selectionDate предпочитает машиночитаемую дату <time datetime="...">.
+func selectionDate(s *goquery.Selection) string {
+	if datetime := s.AttrOr("datetime", ""); len(strings.TrimSpace(datetime)) > 0 {
+		return datetime
+	}
+	return s.First().Text()
+}
+
+// selectionImage учитывает изображения с отложенной загрузкой,
+// которые хранят адрес в data-src.
+func selectionImage(s *goquery.Selection) string {
+	if dataSrc := s.AttrOr("data-src", ""); len(strings.TrimSpace(dataSrc)) > 0 {
+		return dataSrc
+	}
+	return s.AttrOr("src", "")
+}
+
+// sourceSelectors скомпилированные селекторы источника.
+// Незаданные необязательные селекторы остаются nil.
+type sourceSelectors struct {
+	article *Selector
+	title   *Selector
+	detail  *Selector
+	date    *Selector
+	author  *Selector
+	summary *Selector
+	image   *Selector
+	next    *Selector
+}
+
+func compileSelectors(source Source) (sourceSelectors, error) {
+	var selectors sourceSelectors
+
+	var parse func(string) (Selector, error)
+
+	switch source.SelectorType {
+	case "", config.SelectorCSS:
+		parse = ParseSelector
+	case config.SelectorXPath:
+		parse = ParseXPathSelector
+	default:
+		return selectors, fmt.Errorf("unknown selector type %q", source.SelectorType)
+	}
+
+	// Из разметки schema.org статьи извлекаются без селекторов,
+	// селекторы карточек в этом случае нужны только как запасной вариант.
+	listRequired := source.Extract != config.ExtractJSONLD ||
+		len(strings.TrimSpace(source.ArticleSelector)) > 0 ||
+		len(strings.TrimSpace(source.TitleSelector)) > 0 ||
+		len(strings.TrimSpace(source.DetailSelector)) > 0
+
+	fields := []struct {
+		name     string
+		expr     string
+		required bool
+		dst      **Selector
+	}{
+		{name: "article", expr: source.ArticleSelector, required: listRequired, dst: &selectors.article},
+		{name: "title", expr: source.TitleSelector, required: listRequired, dst: &selectors.title},
+		{name: "detail", expr: source.DetailSelector, required: listRequired, dst: &selectors.detail},
+		{name: "date", expr: source.DateSelector, dst: &selectors.date},
+		{name: "author", expr: source.AuthorSelector, dst: &selectors.author},
+		{name: "summary", expr: source.SummarySelector, dst: &selectors.summary},
+		{name: "image", expr: source.ImageSelector, dst: &selectors.image},
+		{name: "next", expr: source.NextSelector, dst: &selectors.next},
+	}
+
+	for _, field := range fields {
+		if len(strings.TrimSpace(field.expr)) == 0 && !field.required {
+			continue
+		}
+
+		selector, err := parse(field.expr)
+		if err != nil {
+			return selectors, fmt.Errorf("%s selector: %w", field.name, err)
+		}
+
+		*field.dst = &selector
+	}
+
+	// Карточки статей ищутся только селектором без способа извлечения.
+	article := selectors.article
+	if article == nil {
+		return selectors, nil
+	}
+
+	if article.extraction != extractDefault || (article.matcher == nil && article.xpath == nil) {
+		return selectors, fmt.Errorf("article selector: %w %q: expected a plain selector", errInvalidSelector, source.ArticleSelector)
+	}
+
+	return selectors, nil
+}
@@ -0,0 +1,315 @@
+package parsing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestParseSelector(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr       string
+		css        string
+		attr       string
+		extraction extraction
+		wantErr    error
+	}{
+		{expr: "h2 a", css: "h2 a", extraction: extractDefault},
+		{expr: "a@href", css: "a", attr: "href", extraction: extractAttr},
+		{expr: "a.link @data-href", css: "a.link", attr: "data-href", extraction: extractAttr},
+		{expr: "time@datetime", css: "time", attr: "datetime", extraction: extractAttr},
+		{expr: "svg use@xlink:href", css: "svg use", attr: "xlink:href", extraction: extractAttr},
+		{expr: `a[href*="@"]`, css: `a[href*="@"]`, extraction: extractDefault},
+		{expr: `a[href*="@"]@title`, css: `a[href*="@"]`, attr: "title", extraction: extractAttr},
+		{expr: "h2::text", css: "h2", extraction: extractText},
+		{expr: "div.summary::html", css: "div.summary", extraction: extractHTML},
+		{expr: "@aria-label", attr: "aria-label", extraction: extractAttr},
+		{expr: "::html", extraction: extractHTML},
+		{expr: "", wantErr: errEmptySelector},
+		{expr: "   ", wantErr: errEmptySelector},
+		{expr: "h2[", wantErr: errInvalidSelector},
+		{expr: "a::bogus", wantErr: errInvalidSelector},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got, err := ParseSelector(tc.expr)
+
+			// assert
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.css, got.css)
+			require.Equal(t, tc.attr, got.attr)
+			require.Equal(t, tc.extraction, got.extraction)
+		})
+	}
+}
+
+func TestSelectorValue(t *testing.T) {
+	t.Parallel()
+
+	body := `<article title="Card title" data-href="/card">
+  <h2 aria-label="Label title"><span>Text</span> title</h2>
+  <a href="/href" data-href="/data-href">Link</a>
+  <time datetime="2023-12-11">11 Dec</time>
+  <div class="summary"><p>Summary <b>bold</b></p></div>
+</article>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	require.NoError(t, err)
+
+	card := doc.Find("article")
+
+	cases := []struct {
+		expr string
+		def  func(*goquery.Selection) string
+		want string
+	}{
+		{expr: "h2", def: selectionText, want: "Text title"},
+		{expr: "h2::text", def: selectionAttr("href"), want: "Text title"},
+		{expr: "h2@aria-label", def: selectionText, want: "Label title"},
+		{expr: "a", def: selectionAttr("href"), want: "/href"},
+		{expr: "a@data-href", def: selectionAttr("href"), want: "/data-href"},
+		{expr: "time", def: selectionDate, want: "2023-12-11"},
+		{expr: "time::text", def: selectionDate, want: "11 Dec"},
+		{expr: "div.summary::html", def: selectionText, want: "<p>Summary <b>bold</b></p>"},
+		{expr: "@title", def: selectionText, want: "Card title"},
+		{expr: "@data-href", def: selectionAttr("href"), want: "/card"},
+		{expr: "a@missing", def: selectionText, want: ""},
+		{expr: "h3", def: selectionText, want: ""},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			selector, err := ParseSelector(tc.expr)
+			require.NoError(t, err)
+
+			// act
+			got := selector.value(card, tc.def)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSourceValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			source: Source{
+				ArticleSelector: "article",
+				TitleSelector:   "h2@title",
+				DetailSelector:  "a@data-href",
+				DateSelector:    "time@datetime",
+				ImageSelector:   "img@src",
+				SummarySelector: "::html",
+			},
+		},
+		{
+			name: "missing title",
+			source: Source{
+				ArticleSelector: "article",
+				DetailSelector:  "a",
+			},
+			wantErr: true,
+		},
+		{
+			name: "article with attribute",
+			source: Source{
+				ArticleSelector: "article@href",
+				TitleSelector:   "h2",
+				DetailSelector:  "a",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid optional selector",
+			source: Source{
+				ArticleSelector: "article",
+				TitleSelector:   "h2",
+				DetailSelector:  "a",
+				DateSelector:    "time[",
+			},
+			wantErr: true,
+		},
+		{
+			name: "jsonld without selectors",
+			source: Source{
+				Extract: config.ExtractJSONLD,
+			},
+		},
+		{
+			name: "jsonld with incomplete fallback selectors",
+			source: Source{
+				Extract:         config.ExtractJSONLD,
+				ArticleSelector: "article",
+			},
+			wantErr: true,
+		},
+		{
+			name: "jsonpath",
+			source: Source{
+				SelectorType:    config.SelectorJSONPath,
+				ArticleSelector: "$.data.posts[*]",
+				TitleSelector:   "$.title",
+				DetailSelector:  "$.url",
+			},
+		},
+		{
+			name: "invalid jsonpath",
+			source: Source{
+				SelectorType:    config.SelectorJSONPath,
+				ArticleSelector: "$.data.posts[",
+				TitleSelector:   "$.title",
+				DetailSelector:  "$.url",
+			},
+			wantErr: true,
+		},
+		{
+			name: "sitemap without selectors",
+			source: Source{
+				Type:    config.TypeSitemap,
+				Sitemap: config.SitemapRules{Pattern: `^https://dropbox\.tech/`},
+			},
+		},
+		{
+			name: "sitemap with invalid pattern",
+			source: Source{
+				Type:    config.TypeSitemap,
+				Sitemap: config.SitemapRules{Pattern: `^https://dropbox\.tech/(`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid rewrite pattern",
+			source: Source{
+				ArticleSelector: "article",
+				TitleSelector:   "h2",
+				DetailSelector:  "a",
+				Rewrite: config.RewriteRules{
+					Title: []config.RewriteRule{{Pattern: `^Blog:(`}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown date locale",
+			source: Source{
+				ArticleSelector: "article",
+				TitleSelector:   "h2",
+				DetailSelector:  "a",
+				DateLocale:      "fr",
+			},
+			wantErr: true,
+		},
+		{
+			name: "watch without selectors",
+			source: Source{
+				Type: config.TypeWatch,
+			},
+		},
+		{
+			name: "watch with invalid ignore selector",
+			source: Source{
+				Type:  config.TypeWatch,
+				Watch: config.WatchRules{Selector: "main", Ignore: []string{"time["}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown extract mode",
+			source: Source{
+				Extract:         "microformats",
+				ArticleSelector: "article",
+				TitleSelector:   "h2",
+				DetailSelector:  "a",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			err := tc.source.Validate()
+
+			// assert
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestParseXPathSelector(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		expr       string
+		extraction extraction
+		wantErr    error
+	}{
+		{expr: "h2/a", extraction: extractDefault},
+		{expr: "a/@href", extraction: extractText},
+		{expr: "@data-href", extraction: extractText},
+		{expr: "a/attribute::title", extraction: extractText},
+		{expr: "div[@class='summary']::html", extraction: extractHTML},
+		{expr: "normalize-space(td[1])", extraction: extractDefault},
+		{expr: "", wantErr: errEmptySelector},
+		{expr: "//div[", wantErr: errInvalidSelector},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got, err := ParseXPathSelector(tc.expr)
+
+			// assert
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, got.xpath)
+			require.Equal(t, tc.extraction, got.extraction)
+		})
+	}
+}
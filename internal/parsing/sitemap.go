@@ -0,0 +1,219 @@
+package parsing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// defaultSitemapDays окно по дате lastmod, если оно не задано.
+	defaultSitemapDays = 30
+	// maxSitemaps ограничивает количество карт, загружаемых за один обход.
+	maxSitemaps = 50
+)
+
+// sitemapDocument карта сайта <urlset> или индекс карт <sitemapindex>.
+type sitemapDocument struct {
+	XMLName  xml.Name       `xml:""`
+	URLs     []sitemapURL   `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+	// News расширение Google News: заголовок и дата публикации.
+	News struct {
+		Title           string `xml:"title"`
+		PublicationDate string `xml:"publication_date"`
+	} `xml:"news"`
+}
+
+// SitemapURL адрес карты сайта источника.
+func (s Source) SitemapURL() (string, error) {
+	if len(strings.TrimSpace(s.Sitemap.URL)) > 0 {
+		return strings.TrimSpace(s.Sitemap.URL), nil
+	}
+
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(&url.URL{Path: "/sitemap.xml"}).String(), nil
+}
+
+// WalkSitemap обходит карту сайта источника и вложенные индексы и
+// возвращает статьи, адреса которых соответствуют шаблону, а дата
+// lastmod попадает в окно. Заголовок берётся из расширения Google News,
+// статьи без него возвращаются с пустым заголовком.
+func (p *Parser) WalkSitemap(
+	ctx context.Context,
+	source Source,
+	fetch func(ctx context.Context, url string) (string, error),
+) ([]Article, error) {
+	sitemapURL, err := source.SitemapURL()
+	if err != nil {
+		return nil, fmt.Errorf("parser: invalid url %s: %v", source.String(), err)
+	}
+
+	pattern, err := compileSitemapPattern(source.Sitemap.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
+
+	rewrites, err := compileRewrites(source)
+	if err != nil {
+		return nil, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
+
+	days := source.Sitemap.Days
+	if days <= 0 {
+		days = defaultSitemapDays
+	}
+	since := p.now().AddDate(0, 0, -days)
+
+	articles := make([]Article, 0, 10)
+	seen := make(map[string]bool)
+
+	queue := []string{sitemapURL}
+	visited := make(map[string]bool)
+
+	for len(queue) > 0 && len(visited) < maxSitemaps {
+		current := queue[0]
+		queue = queue[1:]
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		body, err := fetch(ctx, current)
+		if err != nil {
+			// Недоступна корневая карта - источник сломан, вложенная - пропускаем.
+			if current == sitemapURL {
+				return nil, fmt.Errorf("parser: %s: %v", source.String(), err)
+			}
+			log.Printf("parser: %s sitemap %s skipped: %v", source.String(), current, err)
+			continue
+		}
+
+		doc, err := parseSitemap(body)
+		if err != nil {
+			if current == sitemapURL {
+				return nil, fmt.Errorf("parser: unable to parse sitemap %s: %v", source.String(), err)
+			}
+			log.Printf("parser: %s sitemap %s skipped: %v", source.String(), current, err)
+			continue
+		}
+
+		log.Printf("parser: %s sitemap %s parsing succeeded", source.String(), current)
+
+		baseURL, err := url.Parse(current)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range doc.Sitemaps {
+			// Карта, не менявшаяся с начала окна, не содержит новых статей.
+			if lastMod := parseDate(entry.LastMod); !lastMod.IsZero() && lastMod.Before(since) {
+				continue
+			}
+
+			if loc, err := resolveLink(baseURL, entry.Loc); err == nil {
+				queue = append(queue, loc)
+			}
+		}
+
+		for _, entry := range doc.URLs {
+			loc, err := resolveLink(baseURL, entry.Loc)
+			if err != nil {
+				continue
+			}
+
+			loc, err = resolveLink(baseURL, rewrites.rewriteURL(loc))
+			if err != nil || (pattern != nil && !pattern.MatchString(loc)) {
+				continue
+			}
+
+			published := parseDate(entry.News.PublicationDate, entry.LastMod)
+			if published.IsZero() || published.Before(since) {
+				continue
+			}
+
+			loc = NormalizeURL(loc, source.URLRules)
+			if seen[loc] {
+				continue
+			}
+			seen[loc] = true
+
+			articles = append(articles, Article{
+				Title:     rewrites.rewriteTitle(formatTitle(entry.News.Title)),
+				DetailURL: loc,
+				Published: published,
+			})
+		}
+	}
+
+	if len(queue) > 0 {
+		log.Printf("parser: %s sitemap limit %d reached, %d sitemaps skipped", source.String(), maxSitemaps, len(queue))
+	}
+
+	return articles, nil
+}
+
+func compileSitemapPattern(pattern string) (*regexp.Regexp, error) {
+	if len(strings.TrimSpace(pattern)) == 0 {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("sitemap pattern %q: %v", pattern, err)
+	}
+
+	return re, nil
+}
+
+// parseSitemap разбирает карту сайта, в том числе сжатую gzip (*.xml.gz).
+func parseSitemap(body string) (sitemapDocument, error) {
+	var doc sitemapDocument
+
+	data := []byte(body)
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return doc, err
+		}
+		defer r.Close()
+
+		if data, err = io.ReadAll(r); err != nil {
+			return doc, err
+		}
+	}
+
+	if err := newFeedDecoder(string(data)).Decode(&doc); err != nil {
+		return doc, err
+	}
+
+	switch doc.XMLName.Local {
+	case "urlset", "sitemapindex":
+	default:
+		return doc, fmt.Errorf("unexpected root element <%s>", doc.XMLName.Local)
+	}
+
+	return doc, nil
+}
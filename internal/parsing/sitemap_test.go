@@ -0,0 +1,266 @@
+package parsing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+	"github.com/denisdubovitskiy/feedparser/internal/fetcher"
+)
+
+const bodySitemapIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://dropbox.tech/sitemap-posts.xml</loc>
+    <lastmod>2023-12-11T10:00:00+00:00</lastmod>
+  </sitemap>
+  <sitemap>
+    <loc>https://dropbox.tech/sitemap-news.xml.gz</loc>
+  </sitemap>
+  <sitemap>
+    <loc>https://dropbox.tech/sitemap-2019.xml</loc>
+    <lastmod>2019-06-01</lastmod>
+  </sitemap>
+</sitemapindex>`
+
+const bodySitemapPosts = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://dropbox.tech/infrastructure/atlas-service-platform</loc>
+    <lastmod>2023-12-11T10:00:00+00:00</lastmod>
+  </url>
+  <url>
+    <loc>https://dropbox.tech/infrastructure/old-post</loc>
+    <lastmod>2023-01-10</lastmod>
+  </url>
+  <url>
+    <loc>https://dropbox.tech/infrastructure/no-lastmod</loc>
+  </url>
+  <url>
+    <loc>https://dropbox.tech/careers</loc>
+    <lastmod>2023-12-10</lastmod>
+  </url>
+</urlset>`
+
+const bodySitemapNews = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+  <url>
+    <loc>https://dropbox.tech/infrastructure/magic-pocket?utm_source=sitemap</loc>
+    <news:news>
+      <news:publication_date>2023-12-05T08:00:00Z</news:publication_date>
+      <news:title>Inside the  Magic Pocket</news:title>
+    </news:news>
+  </url>
+  <url>
+    <loc>https://dropbox.tech/infrastructure/atlas-service-platform</loc>
+    <lastmod>2023-12-11T10:00:00+00:00</lastmod>
+  </url>
+</urlset>`
+
+func gzipString(t *testing.T, s string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.String()
+}
+
+func TestParserWalkSitemap(t *testing.T) {
+	t.Parallel()
+
+	bodies := map[string]string{
+		"https://dropbox.tech/sitemap.xml":         bodySitemapIndex,
+		"https://dropbox.tech/sitemap-posts.xml":   bodySitemapPosts,
+		"https://dropbox.tech/sitemap-news.xml.gz": gzipString(t, bodySitemapNews),
+		"https://dropbox.tech/sitemap-2019.xml":    bodySitemapPosts,
+	}
+
+	cases := []struct {
+		name    string
+		source  Source
+		want    []Article
+		fetched []string
+	}{
+		{
+			name: "sitemap index",
+			source: NewSource("Dropbox infra", "https://dropbox.tech/infrastructure", config.SourceConfig{
+				Type: config.TypeSitemap,
+				Sitemap: config.SitemapRules{
+					Pattern: `^https://dropbox\.tech/infrastructure/`,
+				},
+			}),
+			want: []Article{
+				{
+					DetailURL: "https://dropbox.tech/infrastructure/atlas-service-platform",
+					Published: time.Date(2023, 12, 11, 10, 0, 0, 0, time.UTC),
+				},
+				{
+					Title:     "Inside the Magic Pocket",
+					DetailURL: "https://dropbox.tech/infrastructure/magic-pocket",
+					Published: time.Date(2023, 12, 5, 8, 0, 0, 0, time.UTC),
+				},
+			},
+			fetched: []string{
+				"https://dropbox.tech/sitemap.xml",
+				"https://dropbox.tech/sitemap-posts.xml",
+				"https://dropbox.tech/sitemap-news.xml.gz",
+			},
+		},
+		{
+			name: "single sitemap with a wide window",
+			source: NewSource("Dropbox", "https://dropbox.tech/", config.SourceConfig{
+				Type: config.TypeSitemap,
+				Sitemap: config.SitemapRules{
+					URL:  "https://dropbox.tech/sitemap-posts.xml",
+					Days: 365,
+				},
+			}),
+			want: []Article{
+				{
+					DetailURL: "https://dropbox.tech/infrastructure/atlas-service-platform",
+					Published: time.Date(2023, 12, 11, 10, 0, 0, 0, time.UTC),
+				},
+				{
+					DetailURL: "https://dropbox.tech/infrastructure/old-post",
+					Published: time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					DetailURL: "https://dropbox.tech/careers",
+					Published: time.Date(2023, 12, 10, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			fetched: []string{
+				"https://dropbox.tech/sitemap-posts.xml",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+			parser.now = func() time.Time {
+				return time.Date(2023, 12, 12, 0, 0, 0, 0, time.UTC)
+			}
+
+			var fetched []string
+			fetch := func(_ context.Context, url string) (string, error) {
+				fetched = append(fetched, url)
+				body, ok := bodies[url]
+				if !ok {
+					return "", fmt.Errorf("unexpected status code 404 for %s", url)
+				}
+				return body, nil
+			}
+
+			// act
+			got, err := parser.WalkSitemap(context.Background(), tc.source, fetch)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.fetched, fetched)
+		})
+	}
+}
+
+func TestParserWalkSitemapHTTPGzip(t *testing.T) {
+	t.Parallel()
+
+	contentTypes := []string{"application/x-gzip", "application/octet-stream", ""}
+
+	for _, contentType := range contentTypes {
+		contentType := contentType
+
+		t.Run(contentType, func(t *testing.T) {
+			t.Parallel()
+
+			body := gzipString(t, bodySitemapNews)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Без Content-Type сервер определил бы его по содержимому.
+				w.Header()["Content-Type"] = nil
+				if len(contentType) > 0 {
+					w.Header().Set("Content-Type", contentType)
+				}
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			source := NewSource("Dropbox", "https://dropbox.tech/", config.SourceConfig{
+				Type:    config.TypeSitemap,
+				Sitemap: config.SitemapRules{URL: server.URL + "/sitemap-news.xml.gz"},
+			})
+
+			parser := NewParser()
+			parser.now = func() time.Time {
+				return time.Date(2023, 12, 12, 0, 0, 0, 0, time.UTC)
+			}
+
+			// act
+			got, err := parser.WalkSitemap(context.Background(), source, fetcher.NewHTTPFetcher(server.Client()).Fetch)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, []Article{
+				{
+					Title:     "Inside the Magic Pocket",
+					DetailURL: "https://dropbox.tech/infrastructure/magic-pocket",
+					Published: time.Date(2023, 12, 5, 8, 0, 0, 0, time.UTC),
+				},
+				{
+					DetailURL: "https://dropbox.tech/infrastructure/atlas-service-platform",
+					Published: time.Date(2023, 12, 11, 10, 0, 0, 0, time.UTC),
+				},
+			}, got)
+		})
+	}
+}
+
+func TestParserWalkSitemapErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{name: "unavailable", err: fmt.Errorf("unexpected status code 404")},
+		{name: "not a sitemap", body: `<html><body>Not found</body></html>`},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewSource("Example", "https://example.com/blog/", config.SourceConfig{Type: config.TypeSitemap})
+			fetch := func(context.Context, string) (string, error) {
+				return tc.body, tc.err
+			}
+
+			// act
+			_, err := NewParser().WalkSitemap(context.Background(), source, fetch)
+
+			// assert
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package parsing
+
+import (
+	"encoding/json"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// articleTypes типы schema.org, описывающие публикацию.
+var articleTypes = map[string]bool{
+	"Article":             true,
+	"BlogPosting":         true,
+	"NewsArticle":         true,
+	"TechArticle":         true,
+	"ScholarlyArticle":    true,
+	"Report":              true,
+	"SocialMediaPosting":  true,
+	"LiveBlogPosting":     true,
+	"AnalysisNewsArticle": true,
+}
+
+// parseStructuredData извлекает статьи из разметки schema.org:
+// JSON-LD (BlogPosting, ItemList и т.п.) и microdata. Каждая найденная
+// публикация считается карточкой, повторы по адресу отбрасываются.
+func parseStructuredData(source Source, rewrites rewriteRules, baseURL *url.URL, doc *goquery.Document) ParseResult {
+	result := ParseResult{Articles: make([]Article, 0, 10)}
+	seen := make(map[string]bool)
+
+	add := func(item Article) {
+		article, ok := result.newArticle(result.Matched, source, rewrites, baseURL, item.Title, item.DetailURL)
+		result.Matched++
+		if !ok {
+			return
+		}
+
+		if seen[article.DetailURL] {
+			result.Matched--
+			return
+		}
+		seen[article.DetailURL] = true
+
+		article.Published = item.Published
+		article.Summary = formatTitle(item.Summary)
+		article.Author = formatTitle(item.Author)
+
+		if len(item.ImageURL) > 0 {
+			article.ImageURL, _ = resolveLink(baseURL, item.ImageURL)
+		}
+
+		result.Articles = append(result.Articles, article)
+	}
+
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, script *goquery.Selection) {
+		var data any
+		if err := json.Unmarshal([]byte(script.Text()), &data); err != nil {
+			log.Printf("parser: %s invalid json-ld block %d: %v", source.String(), i, err)
+			return
+		}
+
+		walkJSONLD(data, add)
+	})
+
+	doc.Find("[itemscope][itemtype]").Each(func(_ int, scope *goquery.Selection) {
+		if !articleTypes[schemaType(scope.AttrOr("itemtype", ""))] {
+			return
+		}
+
+		add(Article{
+			Title:     firstNonEmpty(microdataProp(scope, "headline"), microdataProp(scope, "name")),
+			DetailURL: firstNonEmpty(microdataProp(scope, "url"), microdataProp(scope, "mainEntityOfPage")),
+			Published: parseDate(microdataProp(scope, "datePublished"), microdataProp(scope, "dateCreated")),
+			Author:    microdataProp(scope, "author"),
+			Summary:   microdataProp(scope, "description"),
+			ImageURL:  microdataProp(scope, "image"),
+		})
+	})
+
+	return result
+}
+
+// walkJSONLD обходит документ JSON-LD, включая @graph и вложенные
+// объекты, и передаёт найденные публикации в add.
+func walkJSONLD(data any, add func(Article)) {
+	switch v := data.(type) {
+	case []any:
+		for _, item := range v {
+			walkJSONLD(item, add)
+		}
+	case map[string]any:
+		switch {
+		case hasJSONLDType(v, articleTypes):
+			add(Article{
+				Title:     firstNonEmpty(jsonldString(v["headline"]), jsonldString(v["name"])),
+				DetailURL: firstNonEmpty(jsonldString(v["url"]), jsonldID(v["mainEntityOfPage"]), jsonldID(v)),
+				Published: parseDate(jsonldString(v["datePublished"]), jsonldString(v["dateCreated"])),
+				Author:    jsonldName(v["author"]),
+				Summary:   jsonldString(v["description"]),
+				ImageURL:  jsonldURL(v["image"]),
+			})
+			return
+		case hasJSONLDType(v, map[string]bool{"ListItem": true}):
+			// Элемент ItemList ссылается на статью адресом или объектом.
+			if item, ok := v["item"].(map[string]any); ok && hasJSONLDType(item, articleTypes) {
+				walkJSONLD(item, add)
+				return
+			}
+
+			add(Article{
+				Title:     firstNonEmpty(jsonldString(v["name"]), jsonldName(v["item"])),
+				DetailURL: firstNonEmpty(jsonldString(v["url"]), jsonldURL(v["item"])),
+			})
+			return
+		}
+
+		// Порядок ключей объекта не определён, обходим их по алфавиту,
+		// чтобы порядок статей не менялся между обходами.
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			walkJSONLD(v[key], add)
+		}
+	}
+}
+
+// hasJSONLDType проверяет @type объекта, который может быть
+// строкой или списком строк.
+func hasJSONLDType(v map[string]any, types map[string]bool) bool {
+	switch t := v["@type"].(type) {
+	case string:
+		return types[schemaType(t)]
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && types[schemaType(s)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaType отбрасывает пространство имён: "https://schema.org/BlogPosting" -> "BlogPosting".
+func schemaType(t string) string {
+	t = strings.TrimSpace(t)
+	if i := strings.LastIndexAny(t, "/:"); i >= 0 {
+		t = t[i+1:]
+	}
+	return t
+}
+
+func jsonldString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []any:
+		if len(s) > 0 {
+			return jsonldString(s[0])
+		}
+	}
+	return ""
+}
+
+// jsonldID адрес объекта: строка или @id/url вложенного объекта.
+func jsonldID(v any) string {
+	switch o := v.(type) {
+	case string:
+		return o
+	case map[string]any:
+		return firstNonEmpty(jsonldString(o["@id"]), jsonldString(o["url"]))
+	}
+	return ""
+}
+
+// jsonldURL адрес из строки, объекта (ImageObject) или списка.
+func jsonldURL(v any) string {
+	switch o := v.(type) {
+	case string:
+		return o
+	case []any:
+		for _, item := range o {
+			if u := jsonldURL(item); len(u) > 0 {
+				return u
+			}
+		}
+	case map[string]any:
+		return firstNonEmpty(jsonldString(o["url"]), jsonldString(o["contentUrl"]), jsonldString(o["@id"]))
+	}
+	return ""
+}
+
+// jsonldName имя из строки, объекта (Person) или списка авторов.
+func jsonldName(v any) string {
+	switch o := v.(type) {
+	case string:
+		return o
+	case []any:
+		names := make([]string, 0, len(o))
+		for _, item := range o {
+			if name := jsonldName(item); len(name) > 0 {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	case map[string]any:
+		return firstNonEmpty(jsonldString(o["name"]), jsonldString(o["headline"]))
+	}
+	return ""
+}
+
+// microdataProp значение первого свойства itemprop, принадлежащего
+// элементу scope, а не вложенному в него элементу itemscope.
+func microdataProp(scope *goquery.Selection, name string) string {
+	var value string
+
+	scope.Find("[itemprop]").EachWithBreak(func(_ int, prop *goquery.Selection) bool {
+		if !hasField(prop.AttrOr("itemprop", ""), name) {
+			return true
+		}
+
+		if owner := prop.ParentsFiltered("[itemscope]").First(); owner.Length() == 0 || owner.Get(0) != scope.Get(0) {
+			return true
+		}
+
+		value = strings.TrimSpace(microdataValue(prop))
+		return len(value) == 0
+	})
+
+	return value
+}
+
+// microdataValue значение свойства по правилам спецификации microdata.
+func microdataValue(prop *goquery.Selection) string {
+	if _, ok := prop.Attr("itemscope"); ok {
+		// Вложенный объект, например автор: берём его имя.
+		return firstNonEmpty(microdataProp(prop, "name"), prop.AttrOr("content", ""), prop.Text())
+	}
+
+	switch goquery.NodeName(prop) {
+	case "meta":
+		return prop.AttrOr("content", "")
+	case "a", "link", "area":
+		return prop.AttrOr("href", "")
+	case "img", "audio", "video", "source", "iframe", "embed":
+		return prop.AttrOr("src", "")
+	case "time":
+		return firstNonEmpty(prop.AttrOr("datetime", ""), prop.Text())
+	case "data", "meter":
+		return prop.AttrOr("value", "")
+	}
+
+	return firstNonEmpty(prop.AttrOr("content", ""), prop.Text())
+}
+
+func hasField(fields, name string) bool {
+	for _, field := range strings.Fields(fields) {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
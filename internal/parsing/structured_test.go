@@ -0,0 +1,179 @@
+package parsing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+func TestParserStructuredData(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		source Source
+		body   string
+		want   []Article
+	}{
+		{
+			name: "json-ld blog postings",
+			source: Source{
+				URL:     "https://example.com/blog/",
+				Extract: config.ExtractJSONLD,
+			},
+			body: `<html><head>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@type": "Blog",
+  "blogPost": [
+    {
+      "@type": "BlogPosting",
+      "headline": "First  post",
+      "url": "/blog/first/",
+      "datePublished": "2023-12-11T10:00:00Z",
+      "author": {"@type": "Person", "name": "Jane Doe"},
+      "description": "First summary",
+      "image": {"@type": "ImageObject", "url": "/images/first.png"}
+    },
+    {
+      "@type": ["BlogPosting", "TechArticle"],
+      "headline": "Second post",
+      "mainEntityOfPage": {"@type": "WebPage", "@id": "https://example.com/blog/second/"},
+      "author": [{"name": "John"}, {"name": "Jane"}]
+    }
+  ]
+}
+</script>
+</head><body></body></html>`,
+			want: []Article{
+				{
+					Title:     "First post",
+					DetailURL: "https://example.com/blog/first/",
+					Published: time.Date(2023, 12, 11, 10, 0, 0, 0, time.UTC),
+					Author:    "Jane Doe",
+					Summary:   "First summary",
+					ImageURL:  "https://example.com/images/first.png",
+				},
+				{
+					Title:     "Second post",
+					DetailURL: "https://example.com/blog/second/",
+					Author:    "John, Jane",
+				},
+			},
+		},
+		{
+			name: "json-ld item list in graph",
+			source: Source{
+				URL:     "https://example.com/blog/",
+				Extract: config.ExtractJSONLD,
+			},
+			body: `<html><head>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {"@type": "WebSite", "url": "https://example.com/", "name": "Example"},
+    {
+      "@type": "ItemList",
+      "itemListElement": [
+        {"@type": "ListItem", "position": 1, "url": "https://example.com/blog/first/", "name": "First post"},
+        {"@type": "ListItem", "position": 2, "item": {"@type": "NewsArticle", "headline": "Second post", "url": "https://example.com/blog/second/"}},
+        {"@type": "ListItem", "position": 3, "url": "https://example.com/blog/first/", "name": "First post"}
+      ]
+    }
+  ]
+}
+</script>
+<script type="application/ld+json">{ invalid json </script>
+</head><body></body></html>`,
+			want: []Article{
+				{
+					Title:     "First post",
+					DetailURL: "https://example.com/blog/first/",
+				},
+				{
+					Title:     "Second post",
+					DetailURL: "https://example.com/blog/second/",
+				},
+			},
+		},
+		{
+			name: "microdata",
+			source: Source{
+				URL:     "https://example.com/blog/",
+				Extract: config.ExtractJSONLD,
+			},
+			body: `<html><body>
+<div itemscope itemtype="https://schema.org/Blog">
+  <meta itemprop="name" content="Example blog">
+  <article itemprop="blogPost" itemscope itemtype="https://schema.org/BlogPosting">
+    <h2 itemprop="headline"><a itemprop="url" href="/blog/first/">First post</a></h2>
+    <time itemprop="datePublished" datetime="2023-12-11">11 Dec</time>
+    <span itemprop="author" itemscope itemtype="https://schema.org/Person">
+      <span itemprop="name">Jane Doe</span>
+    </span>
+    <img itemprop="image" src="/images/first.png">
+  </article>
+</div>
+</body></html>`,
+			want: []Article{
+				{
+					Title:     "First post",
+					DetailURL: "https://example.com/blog/first/",
+					Published: time.Date(2023, 12, 11, 0, 0, 0, 0, time.UTC),
+					Author:    "Jane Doe",
+					ImageURL:  "https://example.com/images/first.png",
+				},
+			},
+		},
+		{
+			name: "fallback to selectors",
+			source: Source{
+				URL:             "https://example.com/blog/",
+				Extract:         config.ExtractJSONLD,
+				ArticleSelector: "article",
+				TitleSelector:   "h2",
+				DetailSelector:  "a",
+			},
+			body: `<html><body>
+<article><h2>First post</h2><a href="/blog/first/">Read</a></article>
+</body></html>`,
+			want: []Article{
+				{
+					Title:     "First post",
+					DetailURL: "https://example.com/blog/first/",
+				},
+			},
+		},
+		{
+			name: "no structured data and no selectors",
+			source: Source{
+				URL:     "https://example.com/blog/",
+				Extract: config.ExtractJSONLD,
+			},
+			body: `<html><body><article><h2>First post</h2></article></body></html>`,
+			want: []Article{},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.Parse(tc.source, tc.body)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.Articles)
+		})
+	}
+}
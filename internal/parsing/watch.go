@@ -0,0 +1,297 @@
+package parsing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+var errNoWatchRegion = errors.New("no watch region matched")
+
+// maxDiffLines количество изменённых строк в описании изменения.
+const maxDiffLines = 5
+
+// maxDiffTableLines наибольшее количество различающихся строк каждого
+// снимка, для которых строится таблица LCS размером n×m. При перестройке
+// большой страницы изменение описывается только количеством строк.
+const maxDiffTableLines = 2000
+
+// blockElements элементы, с которых начинается новая строка текста области.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"br": true, "dd": true, "details": true, "div": true, "dl": true,
+	"dt": true, "figcaption": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "summary": true,
+	"table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+// skippedElements элементы, текст которых не отображается.
+var skippedElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+}
+
+// Snapshot нормализованный текст отслеживаемой области страницы.
+type Snapshot struct {
+	// Title заголовок страницы.
+	Title string
+	// Text текст области по строкам, без пустых строк и лишних пробелов.
+	Text string
+	// Hash SHA-256 текста в шестнадцатеричном виде.
+	Hash string
+}
+
+// watchSelectors скомпилированные селекторы области и игнорируемых элементов.
+type watchSelectors struct {
+	region *Selector
+	ignore []Selector
+}
+
+func compileWatchSelectors(source Source) (watchSelectors, error) {
+	var selectors watchSelectors
+
+	var parse func(string) (Selector, error)
+
+	switch source.SelectorType {
+	case "", config.SelectorCSS:
+		parse = ParseSelector
+	case config.SelectorXPath:
+		parse = ParseXPathSelector
+	default:
+		return selectors, fmt.Errorf("unknown selector type %q", source.SelectorType)
+	}
+
+	if expr := source.Watch.Selector; len(strings.TrimSpace(expr)) > 0 {
+		region, err := parse(expr)
+		if err != nil {
+			return selectors, fmt.Errorf("watch selector: %w", err)
+		}
+		selectors.region = &region
+	}
+
+	for _, expr := range source.Watch.Ignore {
+		ignore, err := parse(expr)
+		if err != nil {
+			return selectors, fmt.Errorf("watch ignore selector: %w", err)
+		}
+		selectors.ignore = append(selectors.ignore, ignore)
+	}
+
+	return selectors, nil
+}
+
+// Snapshot извлекает нормализованный текст отслеживаемой области страницы.
+// Область, не найденная на странице, - ошибка: селектор устарел.
+func (p *Parser) Snapshot(source Source, body string) (Snapshot, error) {
+	var snapshot Snapshot
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return snapshot, fmt.Errorf("parser: unable to parse %s: %v", source.String(), err)
+	}
+
+	selectors, err := compileWatchSelectors(source)
+	if err != nil {
+		return snapshot, fmt.Errorf("parser: %s: %v", source.String(), err)
+	}
+
+	region := doc.Find("body")
+	if selectors.region != nil {
+		region = selectors.region.find(doc.Selection)
+	}
+
+	if region.Length() == 0 {
+		return snapshot, fmt.Errorf("parser: %s: %w", source.String(), errNoWatchRegion)
+	}
+
+	for _, ignore := range selectors.ignore {
+		ignore.find(region).Remove()
+	}
+
+	var lines []string
+	region.Each(func(_ int, s *goquery.Selection) {
+		for _, node := range s.Nodes {
+			lines = appendTextLines(lines, node)
+		}
+	})
+
+	snapshot.Title = formatTitle(doc.Find("title").First().Text())
+	snapshot.Text = strings.Join(lines, "\n")
+
+	sum := sha256.Sum256([]byte(snapshot.Text))
+	snapshot.Hash = hex.EncodeToString(sum[:])
+
+	return snapshot, nil
+}
+
+// appendTextLines добавляет текст узла по строкам: блочные элементы
+// начинают новую строку, пробелы внутри строки схлопываются.
+func appendTextLines(lines []string, node *html.Node) []string {
+	var current strings.Builder
+
+	flush := func() {
+		if line := formatTitle(current.String()); len(line) > 0 {
+			lines = append(lines, line)
+		}
+		current.Reset()
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			current.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if skippedElements[n.Data] {
+				return
+			}
+		}
+
+		block := n.Type == html.ElementNode && blockElements[n.Data]
+		if block {
+			flush()
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+
+		if block {
+			flush()
+		}
+	}
+
+	walk(node)
+	flush()
+
+	return lines
+}
+
+// ChangeArticle создаёт статью об изменении области страницы. Адрес статьи -
+// адрес страницы с фрагментом из пары снимков и времени изменения, чтобы
+// каждое изменение сохранялось отдельно, даже если страница вернулась
+// к прежнему виду и изменилась снова. Фрагмент начинается с "!", поэтому
+// NormalizeURL его сохраняет.
+func (p *Parser) ChangeArticle(source Source, previous, current Snapshot) Article {
+	now := p.now().UTC()
+
+	title := formatTitle(source.Watch.Title)
+	if len(title) == 0 {
+		title = current.Title
+	}
+	if len(title) == 0 {
+		title = source.Name
+	}
+
+	return Article{
+		Title:     title,
+		DetailURL: fmt.Sprintf("%s#!changed-%.8s-%.8s-%d", strings.SplitN(source.URL, "#", 2)[0], previous.Hash, current.Hash, now.Unix()),
+		Published: now,
+		Summary:   DiffSummary(previous.Text, current.Text),
+		// Страница не является статьёй, дополнять нечем.
+		Enriched: true,
+	}
+}
+
+// DiffSummary краткое описание изменений текста по строкам:
+// количество добавленных и удалённых строк и первые из них.
+func DiffSummary(previous, current string) string {
+	previousLines, currentLines := trimCommonLines(splitLines(previous), splitLines(current))
+
+	if len(previousLines) > maxDiffTableLines || len(currentLines) > maxDiffTableLines {
+		return fmt.Sprintf("+%d / -%d lines changed", len(currentLines), len(previousLines))
+	}
+
+	removed, added := diffLines(previousLines, currentLines)
+
+	if len(removed) == 0 && len(added) == 0 {
+		return ""
+	}
+
+	summary := []string{fmt.Sprintf("+%d / -%d lines", len(added), len(removed))}
+
+	changed := make([]string, 0, maxDiffLines)
+	for _, line := range added {
+		changed = append(changed, "+ "+line)
+	}
+	for _, line := range removed {
+		changed = append(changed, "- "+line)
+	}
+
+	if len(changed) > maxDiffLines {
+		changed = append(changed[:maxDiffLines], "…")
+	}
+
+	return strings.Join(append(summary, changed...), "\n")
+}
+
+func splitLines(text string) []string {
+	if len(text) == 0 {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// trimCommonLines отбрасывает общие начало и конец снимков: обычно
+// меняется несколько строк, и таблица LCS остаётся маленькой.
+func trimCommonLines(previous, current []string) ([]string, []string) {
+	for len(previous) > 0 && len(current) > 0 && previous[0] == current[0] {
+		previous, current = previous[1:], current[1:]
+	}
+
+	for len(previous) > 0 && len(current) > 0 && previous[len(previous)-1] == current[len(current)-1] {
+		previous, current = previous[:len(previous)-1], current[:len(current)-1]
+	}
+
+	return previous, current
+}
+
+// diffLines сравнивает строки через наибольшую общую подпоследовательность
+// и возвращает удалённые и добавленные строки в порядке следования.
+func diffLines(previous, current []string) (removed, added []string) {
+	n, m := len(previous), len(current)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if previous[i] == current[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case previous[i] == current[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			removed = append(removed, previous[i])
+			i++
+		default:
+			added = append(added, current[j])
+			j++
+		}
+	}
+
+	removed = append(removed, previous[i:]...)
+	added = append(added, current[j:]...)
+
+	return removed, added
+}
@@ -0,0 +1,259 @@
+package parsing
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/config"
+)
+
+const bodyPricing = `<html><head><title>Pricing | Example</title></head><body>
+<nav><a href="/">Home</a> <a href="/blog">Blog</a></nav>
+<main id="pricing">
+  <h1>Plans</h1>
+  <div class="plan"><h2>Free</h2><p>Up to   3 users</p></div>
+  <div class="plan"><h2>Team</h2><p>$15 per user<br>Priority support</p></div>
+  <p class="updated">Updated 5 minutes ago</p>
+  <script>window.plans = {}</script>
+</main>
+</body></html>`
+
+func TestParserSnapshot(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		source   Source
+		wantText string
+		wantErr  error
+	}{
+		{
+			name: "region",
+			source: Source{
+				Type:  config.TypeWatch,
+				Watch: config.WatchRules{Selector: "#pricing", Ignore: []string{".updated"}},
+			},
+			wantText: "Plans\nFree\nUp to 3 users\nTeam\n$15 per user\nPriority support",
+		},
+		{
+			name: "xpath region",
+			source: Source{
+				Type:         config.TypeWatch,
+				SelectorType: config.SelectorXPath,
+				Watch:        config.WatchRules{Selector: `//div[@class="plan"][2]`},
+			},
+			wantText: "Team\n$15 per user\nPriority support",
+		},
+		{
+			name:     "whole body",
+			source:   Source{Type: config.TypeWatch},
+			wantText: "Home Blog\nPlans\nFree\nUp to 3 users\nTeam\n$15 per user\nPriority support\nUpdated 5 minutes ago",
+		},
+		{
+			name: "region not found",
+			source: Source{
+				Type:  config.TypeWatch,
+				Watch: config.WatchRules{Selector: "#prices"},
+			},
+			wantErr: errNoWatchRegion,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewParser()
+
+			// act
+			got, err := parser.Snapshot(tc.source, bodyPricing)
+
+			// assert
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "Pricing | Example", got.Title)
+			require.Equal(t, tc.wantText, got.Text)
+			require.Len(t, got.Hash, 64)
+		})
+	}
+
+	t.Run("ignored changes keep the hash", func(t *testing.T) {
+		t.Parallel()
+
+		source := Source{
+			Type:  config.TypeWatch,
+			Watch: config.WatchRules{Selector: "#pricing", Ignore: []string{".updated"}},
+		}
+
+		parser := NewParser()
+
+		// act
+		before, err := parser.Snapshot(source, bodyPricing)
+		require.NoError(t, err)
+
+		after, err := parser.Snapshot(source, strings.Replace(bodyPricing, "5 minutes ago", "1 hour ago", 1))
+		require.NoError(t, err)
+
+		// assert
+		require.Equal(t, before.Hash, after.Hash)
+	})
+}
+
+func TestDiffSummary(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		previous string
+		current  string
+		want     string
+	}{
+		{
+			name:     "unchanged",
+			previous: "Plans\nTeam\n$15 per user",
+			current:  "Plans\nTeam\n$15 per user",
+			want:     "",
+		},
+		{
+			name:     "changed line",
+			previous: "Plans\nTeam\n$15 per user\nPriority support",
+			current:  "Plans\nTeam\n$20 per user\nPriority support",
+			want:     "+1 / -1 lines\n+ $20 per user\n- $15 per user",
+		},
+		{
+			name:     "added lines",
+			previous: "Plans\nFree",
+			current:  "Plans\nFree\nEnterprise\nContact us",
+			want:     "+2 / -0 lines\n+ Enterprise\n+ Contact us",
+		},
+		{
+			name:     "many changes",
+			previous: "a\nb\nc",
+			current:  "d\ne\nf\ng",
+			want:     "+4 / -3 lines\n+ d\n+ e\n+ f\n+ g\n- a\n…",
+		},
+		{
+			name:     "restructured large page",
+			previous: "Header\n" + numberedLines("old", maxDiffTableLines+1) + "\nFooter",
+			current:  "Header\n" + numberedLines("new", 10) + "\nFooter",
+			want:     fmt.Sprintf("+10 / -%d lines changed", maxDiffTableLines+1),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got := DiffSummary(tc.previous, tc.current)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParserChangeArticle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+	parser := &Parser{now: func() time.Time { return now }}
+
+	previous := Snapshot{Title: "Pricing", Text: "Team\n$15 per user", Hash: "0123456789abcdef"}
+	current := Snapshot{Title: "Pricing", Text: "Team\n$20 per user", Hash: "fedcba9876543210"}
+
+	cases := []struct {
+		name   string
+		source Source
+		want   Article
+	}{
+		{
+			name:   "page title",
+			source: Source{Name: "Example", URL: "https://example.com/pricing"},
+			want: Article{
+				Title:     "Pricing",
+				DetailURL: "https://example.com/pricing#!changed-01234567-fedcba98-1704888000",
+				Published: now,
+				Summary:   "+1 / -1 lines\n+ $20 per user\n- $15 per user",
+				Enriched:  true,
+			},
+		},
+		{
+			name: "configured title",
+			source: Source{
+				Name:  "Example",
+				URL:   "https://example.com/pricing#plans",
+				Watch: config.WatchRules{Title: "Example pricing changed"},
+			},
+			want: Article{
+				Title:     "Example pricing changed",
+				DetailURL: "https://example.com/pricing#!changed-01234567-fedcba98-1704888000",
+				Published: now,
+				Summary:   "+1 / -1 lines\n+ $20 per user\n- $15 per user",
+				Enriched:  true,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got := parser.ChangeArticle(tc.source, previous, current)
+
+			// assert
+			require.Equal(t, tc.want, got)
+			// Нормализация не должна объединять разные изменения.
+			require.Equal(t, got.DetailURL, NormalizeURL(got.DetailURL, config.URLRules{}))
+		})
+	}
+}
+
+// numberedLines строки "<prefix> 0", "<prefix> 1" и далее.
+func numberedLines(prefix string, n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s %d", prefix, i)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestParserChangeArticleToggle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.UTC)
+	parser := &Parser{now: func() time.Time { return now }}
+
+	source := Source{Name: "Example", URL: "https://example.com/pricing"}
+	a := Snapshot{Text: "Team\n$15 per user", Hash: "0123456789abcdef"}
+	b := Snapshot{Text: "Team\n$20 per user", Hash: "fedcba9876543210"}
+
+	// act
+	first := parser.ChangeArticle(source, a, b)
+	now = now.Add(time.Hour)
+	parser.ChangeArticle(source, b, a)
+	now = now.Add(time.Hour)
+	second := parser.ChangeArticle(source, a, b)
+
+	// assert
+	require.NotEqual(t, first.DetailURL, second.DetailURL)
+	require.NotEqual(t,
+		NormalizeURL(first.DetailURL, config.URLRules{}),
+		NormalizeURL(second.DetailURL, config.URLRules{}),
+	)
+}
@@ -0,0 +1,123 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/denisdubovitskiy/feedparser/internal/database"
+	"github.com/denisdubovitskiy/feedparser/internal/unix"
+)
+
+// baselineCrawls количество удачных обходов, по которым оценивается
+// обычное для источника количество карточек.
+const baselineCrawls = 5
+
+// BreakageRules пороги, после которых источник считается сломанным.
+type BreakageRules struct {
+	// After количество неудачных запусков раннера подряд. Повторные
+	// попытки одного запуска считаются одним обходом.
+	After int
+	// DropRatio доля от обычного количества карточек, ниже которой
+	// обход считается неудачным.
+	DropRatio float64
+	// Attempts наибольшее количество попыток обхода за один запуск.
+	Attempts int
+}
+
+// HistoryLimit количество последних сохранённых попыток, необходимых для проверки.
+func (r BreakageRules) HistoryLimit() int64 {
+	attempts := r.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	return int64((r.After + baselineCrawls) * attempts)
+}
+
+// Breakage признаки поломки источника.
+type Breakage struct {
+	Reason string
+	// LastGood время завершения последнего удачного обхода, нулевое, если неизвестно.
+	LastGood time.Time
+}
+
+// DetectBreakage проверяет последние обходы источника, начиная с самого нового.
+// Обход неудачен, если не нашлось ни одной карточки или их количество резко
+// упало относительно предыдущих обходов. Обходы, на которых не загрузилось
+// ни одной страницы, не учитываются: это сбой сети, а не селекторов.
+// Из попыток одного запуска раннера учитывается только последняя.
+func DetectBreakage(crawls []database.Crawl, rules BreakageRules) (Breakage, bool) {
+	if rules.After <= 0 {
+		return Breakage{}, false
+	}
+
+	loaded := make([]database.Crawl, 0, len(crawls))
+	for i, crawl := range crawls {
+		// Обходы до появления запусков в истории считаются по отдельности.
+		if i > 0 && crawl.Run != 0 && crawl.Run == crawls[i-1].Run {
+			continue
+		}
+
+		if crawl.Pages > 0 {
+			loaded = append(loaded, crawl)
+		}
+	}
+
+	if len(loaded) < rules.After {
+		return Breakage{}, false
+	}
+
+	streak, history := loaded[:rules.After], loaded[rules.After:]
+
+	var (
+		breakage Breakage
+		baseline float64
+		good     int
+	)
+
+	for _, crawl := range history {
+		if crawl.Matched == 0 {
+			continue
+		}
+
+		if good == 0 {
+			breakage.LastGood = unix.ToTime(crawl.Finished)
+		}
+
+		baseline += float64(crawl.Matched)
+		good++
+
+		if good == baselineCrawls {
+			break
+		}
+	}
+
+	if good > 0 {
+		baseline /= float64(good)
+	}
+
+	zero := true
+
+	for _, crawl := range streak {
+		if crawl.Matched == 0 {
+			continue
+		}
+
+		zero = false
+
+		// Без истории удачных обходов падение оценить не с чем.
+		if good == 0 || float64(crawl.Matched) >= baseline*rules.DropRatio {
+			return Breakage{}, false
+		}
+	}
+
+	if zero {
+		breakage.Reason = fmt.Sprintf("no cards matched in %d consecutive crawls", rules.After)
+	} else {
+		breakage.Reason = fmt.Sprintf(
+			"matched cards dropped from %.0f to %d in %d consecutive crawls",
+			baseline, streak[0].Matched, rules.After,
+		)
+	}
+
+	return breakage, true
+}
@@ -0,0 +1,140 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/database"
+	"github.com/denisdubovitskiy/feedparser/internal/unix"
+)
+
+func TestDetectBreakage(t *testing.T) {
+	t.Parallel()
+
+	lastGood := time.Date(2023, time.November, 20, 10, 0, 0, 0, time.UTC)
+
+	crawl := func(matched int64) database.Crawl {
+		return database.Crawl{Pages: 1, Matched: matched, Finished: unix.FromTime(lastGood)}
+	}
+
+	// attempt попытка обхода в запуске раннера run.
+	attempt := func(run, matched int64) database.Crawl {
+		c := crawl(matched)
+		c.Run = run
+		return c
+	}
+
+	rules := BreakageRules{After: 3, DropRatio: 0.5, Attempts: 3}
+
+	cases := []struct {
+		name       string
+		crawls     []database.Crawl
+		want       Breakage
+		wantBroken bool
+	}{
+		{
+			name:   "healthy",
+			crawls: []database.Crawl{crawl(10), crawl(10), crawl(9), crawl(10)},
+		},
+		{
+			name:   "quiet source",
+			crawls: []database.Crawl{crawl(10), crawl(10), crawl(10), crawl(10)},
+		},
+		{
+			name:       "no cards matched",
+			crawls:     []database.Crawl{crawl(0), crawl(0), crawl(0), crawl(10)},
+			want:       Breakage{Reason: "no cards matched in 3 consecutive crawls", LastGood: lastGood},
+			wantBroken: true,
+		},
+		{
+			name:       "no cards matched without history",
+			crawls:     []database.Crawl{crawl(0), crawl(0), crawl(0)},
+			want:       Breakage{Reason: "no cards matched in 3 consecutive crawls"},
+			wantBroken: true,
+		},
+		{
+			name:   "not enough crawls",
+			crawls: []database.Crawl{crawl(0), crawl(0), crawl(10)},
+		},
+		{
+			name:   "recovered",
+			crawls: []database.Crawl{crawl(10), crawl(0), crawl(0), crawl(0)},
+		},
+		{
+			name:       "sudden drop",
+			crawls:     []database.Crawl{crawl(2), crawl(0), crawl(2), crawl(20), crawl(20)},
+			want:       Breakage{Reason: "matched cards dropped from 20 to 2 in 3 consecutive crawls", LastGood: lastGood},
+			wantBroken: true,
+		},
+		{
+			name:   "small drop",
+			crawls: []database.Crawl{crawl(12), crawl(12), crawl(12), crawl(20), crawl(20)},
+		},
+		{
+			name: "network failures are skipped",
+			crawls: []database.Crawl{
+				crawl(0),
+				{Error: "request failed"},
+				crawl(0),
+				{Error: "request failed"},
+				crawl(0),
+				crawl(10),
+			},
+			want:       Breakage{Reason: "no cards matched in 3 consecutive crawls", LastGood: lastGood},
+			wantBroken: true,
+		},
+		{
+			name: "retries of one run",
+			crawls: []database.Crawl{
+				attempt(4, 0), attempt(4, 0), attempt(4, 0),
+				attempt(3, 10),
+				attempt(2, 10),
+			},
+		},
+		{
+			name: "successful retry",
+			crawls: []database.Crawl{
+				attempt(4, 10), attempt(4, 0),
+				attempt(3, 0), attempt(3, 0), attempt(3, 0),
+				attempt(2, 0), attempt(2, 0), attempt(2, 0),
+				attempt(1, 10),
+			},
+		},
+		{
+			name: "consecutive failed runs",
+			crawls: []database.Crawl{
+				attempt(4, 0), attempt(4, 0), attempt(4, 0),
+				attempt(3, 0), attempt(3, 0), attempt(3, 0),
+				attempt(2, 0), attempt(2, 0), attempt(2, 0),
+				attempt(1, 10),
+			},
+			want:       Breakage{Reason: "no cards matched in 3 consecutive crawls", LastGood: lastGood},
+			wantBroken: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got, broken := DetectBreakage(tc.crawls, rules)
+
+			// assert
+			require.Equal(t, tc.wantBroken, broken)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestBreakageRulesHistoryLimit(t *testing.T) {
+	t.Parallel()
+
+	// act & assert
+	require.Equal(t, int64(24), BreakageRules{After: 3, Attempts: 3}.HistoryLimit())
+	require.Equal(t, int64(8), BreakageRules{After: 3}.HistoryLimit())
+}
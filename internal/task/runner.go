@@ -12,16 +12,30 @@ import (
 	"github.com/denisdubovitskiy/feedparser/internal/unix"
 )
 
-func NewRunner(service *database.Service, maxRetries int64) *Runner {
-	return &Runner{service: service, maxRetries: maxRetries}
+// ErrSkipped источник пропущен без попытки обработки, например пока
+// недоступен браузер. Попытка не расходуется, источник будет обработан
+// при следующем запуске.
+var ErrSkipped = errors.New("skipped")
+
+// NewRunner создаёт раннер, обрабатывающий до workers источников одновременно.
+func NewRunner(service *database.Service, maxRetries int64, workers int) *Runner {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &Runner{service: service, maxRetries: maxRetries, workers: workers}
 }
 
 type Runner struct {
 	service    *database.Service
 	maxRetries int64
+	workers    int
 }
 
-func (r *Runner) ForEachSource(ctx context.Context, f func(source *database.Source) error) error {
+// ForEachSource обрабатывает источники, не посещённые с предыдущего запуска.
+// f вызывается одновременно из нескольких горутин и получает время начала
+// запуска, общее для всех попыток обработать источник.
+func (r *Runner) ForEachSource(ctx context.Context, f func(run time.Time, source *database.Source) error) error {
 	// Перед запуском сбрасываем количество ретраев.
 	if err := r.service.ResetRetries(ctx); err != nil {
 		return fmt.Errorf("runner: unable to reset retries: %v", err)
@@ -59,41 +73,80 @@ func (r *Runner) ForEachSource(ctx context.Context, f func(source *database.Sour
 		}
 	}()
 
+	// done сигнализирует о завершении обработки источника.
+	done := make(chan struct{}, r.workers)
+	active := 0
+
 	for {
+		if active == r.workers {
+			<-done
+			active--
+		}
+
 		// Забираем из базы по одному источнику из тех, чья дата последнего
 		// визита меньше, чем дата предыдущего запуска раннера.
-		source, err := r.service.FetchOne(context.Background(), unixStarted, r.maxRetries)
+		source, err := r.service.ClaimOne(context.Background(), unixStarted, r.maxRetries, unix.TimeNow())
 		if err != nil {
-			// Все источники пройдены.
 			if errors.Is(err, sql.ErrNoRows) {
-				break
+				// Все источники пройдены.
+				if active == 0 {
+					break
+				}
+
+				// Источник, обработка которого завершится сбоем,
+				// снова станет доступен для повторной попытки.
+				<-done
+				active--
+				continue
 			}
 
 			log.Printf("runner: unable to fetch a source: %v", err)
 			continue
 		}
 
-		if err := f(source); err != nil {
-			log.Printf("runner: %s failed to process: %v", source.String(), err)
+		active++
 
-			retriesUpdateErr := r.service.UpdateRetries(context.Background(), source.ID)
-			if retriesUpdateErr != nil {
-				log.Printf("runner: %s failed to update retries: %v", source.String(), retriesUpdateErr)
-			}
+		go func() {
+			defer func() { done <- struct{}{} }()
+			r.process(jobStarted, source, f)
+		}()
+	}
 
-			continue
+	log.Printf("runner: finished, time taken: %f", time.Since(jobStarted).Seconds())
+
+	return finalErr
+}
+
+func (r *Runner) process(run time.Time, source *database.Source, f func(run time.Time, source *database.Source) error) {
+	if err := f(run, source); err != nil {
+		// Отметка о захвате источника остаётся, поэтому в этом запуске
+		// он больше не выбирается, а в следующем снова станет доступен.
+		if errors.Is(err, ErrSkipped) {
+			log.Printf("runner: %s %v", source.String(), err)
+			return
 		}
 
-		updateErr := r.service.UpdateLastVisited(context.Background(), source.ID, unix.TimeNow())
-		if updateErr != nil {
-			log.Printf("runner: %s update error: %v", source.String(), updateErr.Error())
-			continue
+		log.Printf("runner: %s failed to process: %v", source.String(), err)
+
+		retriesUpdateErr := r.service.UpdateRetries(context.Background(), source.ID)
+		if retriesUpdateErr != nil {
+			log.Printf("runner: %s failed to update retries: %v", source.String(), retriesUpdateErr)
 		}
 
-		log.Printf("runner: %s job finished", source.String())
+		// Возвращаем прежнюю дату визита, чтобы источник обработали повторно.
+		restoreErr := r.service.UpdateLastVisited(context.Background(), source.ID, source.LastVisited)
+		if restoreErr != nil {
+			log.Printf("runner: %s failed to restore last visited: %v", source.String(), restoreErr)
+		}
+
+		return
 	}
 
-	log.Printf("runner: finished, time taken: %f", time.Since(jobStarted).Seconds())
+	updateErr := r.service.UpdateLastVisited(context.Background(), source.ID, unix.TimeNow())
+	if updateErr != nil {
+		log.Printf("runner: %s update error: %v", source.String(), updateErr.Error())
+		return
+	}
 
-	return finalErr
+	log.Printf("runner: %s job finished", source.String())
 }
@@ -0,0 +1,163 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/denisdubovitskiy/feedparser/internal/database"
+)
+
+// newTestRunner создаёт раннер над временной базой с count источниками.
+func newTestRunner(t *testing.T, count, workers int) (*Runner, *sql.DB) {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "feedparser.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	service := database.NewService(db)
+
+	for i := 0; i < count; i++ {
+		err := service.UpsertSource(context.Background(), fmt.Sprintf("source-%d", i), fmt.Sprintf("https://example.com/%d", i), "{}")
+		require.NoError(t, err)
+	}
+
+	return NewRunner(service, 3, workers), db
+}
+
+// sourceState возвращает дату визита и количество попыток источника.
+func sourceState(t *testing.T, db *sql.DB, name string) (lastVisited, retries int64) {
+	t.Helper()
+
+	row := db.QueryRow(`SELECT last_visited, retries FROM sources WHERE name = ?`, name)
+	require.NoError(t, row.Scan(&lastVisited, &retries))
+
+	return lastVisited, retries
+}
+
+func TestRunnerForEachSourceConcurrency(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		workers int
+	}{
+		{name: "single worker", workers: 1},
+		{name: "three workers", workers: 3},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner, _ := newTestRunner(t, 10, tc.workers)
+
+			var (
+				mu        sync.Mutex
+				processed = make(map[string]int)
+				active    atomic.Int32
+				peak      atomic.Int32
+			)
+
+			// act
+			err := runner.ForEachSource(context.Background(), func(_ time.Time, source *database.Source) error {
+				n := active.Add(1)
+				defer active.Add(-1)
+
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+
+				mu.Lock()
+				processed[source.Name]++
+				mu.Unlock()
+
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			})
+
+			// assert
+			require.NoError(t, err)
+			require.Len(t, processed, 10)
+			for name, times := range processed {
+				require.Equal(t, 1, times, name)
+			}
+			require.Equal(t, int32(tc.workers), peak.Load())
+		})
+	}
+}
+
+func TestRunnerForEachSourceFailure(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("no cards matched")
+
+	cases := []struct {
+		name        string
+		err         error
+		wantCalls   int
+		wantRetries int64
+		wantVisited bool
+	}{
+		{
+			name:        "failed source is retried with restored visit date",
+			err:         errFailed,
+			wantCalls:   3,
+			wantRetries: 3,
+		},
+		{
+			name:        "skipped source keeps its retries",
+			err:         fmt.Errorf("%w: browser unavailable", ErrSkipped),
+			wantCalls:   1,
+			wantRetries: 0,
+			wantVisited: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner, db := newTestRunner(t, 2, 2)
+
+			var calls atomic.Int32
+
+			// act
+			err := runner.ForEachSource(context.Background(), func(_ time.Time, source *database.Source) error {
+				if source.Name != "source-0" {
+					return nil
+				}
+				calls.Add(1)
+				return tc.err
+			})
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, int32(tc.wantCalls), calls.Load())
+
+			lastVisited, retries := sourceState(t, db, "source-0")
+			require.Equal(t, tc.wantRetries, retries)
+			require.Equal(t, tc.wantVisited, lastVisited > 0)
+
+			lastVisited, retries = sourceState(t, db, "source-1")
+			require.Zero(t, retries)
+			require.Positive(t, lastVisited)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package telegram
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -34,17 +36,31 @@ type Publisher struct {
 	channel string
 }
 
-func (p *Publisher) PublishPost(source, title, url string, channels, tags []string) error {
+// Post публикуемая статья.
+type Post struct {
+	Source string
+	Title  string
+	URL    string
+	// CanonicalURL адрес, указанный самой страницей статьи.
+	// Если задан, используется вместо URL.
+	CanonicalURL string
+	// Description краткое описание статьи.
+	Description string
+	Channels    []string
+	Tags        []string
+}
+
+func (p *Publisher) PublishPost(post Post) error {
 
 	var channelsToSend []string
 
-	if len(channels) == 0 {
+	if len(post.Channels) == 0 {
 		channelsToSend = append(channelsToSend, p.channel)
 	} else {
-		channelsToSend = channels
+		channelsToSend = post.Channels
 	}
 
-	text := formatMessage(source, title, url, tags)
+	text := formatMessage(post)
 
 	for _, channel := range channelsToSend {
 		msg := newMarkdownMessage(formatChannel(channel), text)
@@ -77,6 +93,35 @@ func newMarkdownMessage(channel, text string) tgbotapi.MessageConfig {
 	return msg
 }
 
+// Alert уведомление администратора о сломанном источнике.
+type Alert struct {
+	Source string
+	URL    string
+	Reason string
+	// LastGood время последнего удачного обхода, нулевое, если неизвестно.
+	LastGood time.Time
+}
+
+// PublishAlert отправляет уведомление в чат администратора.
+// Чат задаётся именем канала или числовым идентификатором.
+func (p *Publisher) PublishAlert(chat string, alert Alert) error {
+	msg := newAlertMessage(chat, formatAlert(alert))
+
+	if _, err := p.client.Send(msg); err != nil {
+		return fmt.Errorf("publisher: unable to send an alert: %w", err)
+	}
+
+	return nil
+}
+
+func newAlertMessage(chat, text string) tgbotapi.MessageConfig {
+	if id, err := strconv.ParseInt(chat, 10, 64); err == nil {
+		return tgbotapi.NewMessage(id, text)
+	}
+
+	return tgbotapi.NewMessageToChannel(formatChannel(chat), text)
+}
+
 type RetryError struct {
 	err   error
 	after int
@@ -103,15 +148,68 @@ const (
 	templateBase     = `%s: [%s](%s)`
 	templateWithTags = `%s: [%s](%s)
 %s
+`
+	templateWithDescription = `%s: [%s](%s)
+
+%s`
+	templateWithDescriptionAndTags = `%s: [%s](%s)
+
+%s
+%s
 `
 )
 
-func formatMessage(source, title, url string, tags []string) string {
-	if len(tags) == 0 {
-		return fmt.Sprintf(templateBase, source, title, url)
+// maxDescriptionLength ограничивает описание, чтобы сообщение
+// оставалось анонсом, а не пересказом статьи.
+const maxDescriptionLength = 300
+
+func formatMessage(post Post) string {
+	url := post.URL
+	if len(post.CanonicalURL) > 0 {
+		url = post.CanonicalURL
+	}
+
+	description := formatDescription(post.Description)
+
+	switch {
+	case len(description) == 0 && len(post.Tags) == 0:
+		return fmt.Sprintf(templateBase, post.Source, post.Title, url)
+	case len(description) == 0:
+		return fmt.Sprintf(templateWithTags, post.Source, post.Title, url, formatTags(post.Tags))
+	case len(post.Tags) == 0:
+		return fmt.Sprintf(templateWithDescription, post.Source, post.Title, url, description)
+	}
+
+	return fmt.Sprintf(templateWithDescriptionAndTags, post.Source, post.Title, url, description, formatTags(post.Tags))
+}
+
+const templateAlert = `Source %s looks broken: %s
+%s
+Last good crawl: %s`
+
+// formatAlert форматирует уведомление простым текстом: адреса
+// и причины не экранируются под Markdown.
+func formatAlert(alert Alert) string {
+	lastGood := "unknown"
+	if !alert.LastGood.IsZero() {
+		lastGood = alert.LastGood.UTC().Format(time.DateTime) + " UTC"
+	}
+
+	return fmt.Sprintf(templateAlert, alert.Source, alert.Reason, alert.URL, lastGood)
+}
+
+var markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+
+// formatDescription обрезает описание и экранирует разметку Markdown,
+// которая в произвольном тексте ломает разбор сообщения.
+func formatDescription(description string) string {
+	description = strings.TrimSpace(description)
+
+	if runes := []rune(description); len(runes) > maxDescriptionLength {
+		description = strings.TrimSpace(string(runes[:maxDescriptionLength])) + "…"
 	}
 
-	return fmt.Sprintf(templateWithTags, source, title, url, formatTags(tags))
+	return markdownEscaper.Replace(description)
 }
 
 func formatTags(tags []string) string {
@@ -1,7 +1,9 @@
 package telegram
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gojuno/minimock/v3"
 	"github.com/stretchr/testify/assert"
@@ -28,14 +30,6 @@ func newMk(t *testing.T) (*mk, func()) {
 	return m, mc.Finish
 }
 
-type testArticle struct {
-	source   string
-	title    string
-	url      string
-	channels []string
-	tags     []string
-}
-
 var (
 	emptyChannels    []string
 	emptyTags        []string
@@ -47,13 +41,13 @@ var (
 	nilError   error
 )
 
-func testNewArticle(channels, tags []string) testArticle {
-	return testArticle{
-		source:   "test-source",
-		title:    "test-title",
-		url:      "test-url",
-		channels: channels,
-		tags:     tags,
+func testNewPost(channels, tags []string) Post {
+	return Post{
+		Source:   "test-source",
+		Title:    "test-title",
+		URL:      "test-url",
+		Channels: channels,
+		Tags:     tags,
 	}
 }
 
@@ -67,8 +61,8 @@ func TestSender(t *testing.T) {
 		m, cleanup := newMk(t)
 		defer cleanup()
 
-		article := testNewArticle(emptyChannels, emptyTags)
-		text := formatMessage(article.source, article.title, article.url, article.tags)
+		post := testNewPost(emptyChannels, emptyTags)
+		text := formatMessage(post)
 		wantMessage := newMarkdownMessage(m.publisher.channel, text)
 
 		m.client.SendMock.
@@ -76,13 +70,7 @@ func TestSender(t *testing.T) {
 			Return(nilMessage, nilError)
 
 		// act
-		err := m.publisher.PublishPost(
-			article.source,
-			article.title,
-			article.url,
-			article.channels,
-			article.tags,
-		)
+		err := m.publisher.PublishPost(post)
 
 		// assert
 		require.NoError(t, err)
@@ -94,23 +82,17 @@ func TestSender(t *testing.T) {
 		m, cleanup := newMk(t)
 		defer cleanup()
 
-		article := testNewArticle(multipleChannels, emptyTags)
-		text := formatMessage(article.source, article.title, article.url, article.tags)
+		post := testNewPost(multipleChannels, emptyTags)
+		text := formatMessage(post)
 
-		wantMessage1 := newMarkdownMessage(formatChannel(article.channels[0]), text)
-		wantMessage2 := newMarkdownMessage(formatChannel(article.channels[1]), text)
+		wantMessage1 := newMarkdownMessage(formatChannel(post.Channels[0]), text)
+		wantMessage2 := newMarkdownMessage(formatChannel(post.Channels[1]), text)
 
 		m.client.SendMock.When(wantMessage1).Then(nilMessage, nilError)
 		m.client.SendMock.When(wantMessage2).Then(nilMessage, nilError)
 
 		// act
-		err := m.publisher.PublishPost(
-			article.source,
-			article.title,
-			article.url,
-			article.channels,
-			article.tags,
-		)
+		err := m.publisher.PublishPost(post)
 
 		// assert
 		require.NoError(t, err)
@@ -122,23 +104,159 @@ func TestSender(t *testing.T) {
 		m, cleanup := newMk(t)
 		defer cleanup()
 
-		article := testNewArticle(emptyChannels, emptyTags)
-		text := formatMessage(article.source, article.title, article.url, article.tags)
+		post := testNewPost(emptyChannels, emptyTags)
+		text := formatMessage(post)
 		wantMessage := newMarkdownMessage(m.publisher.channel, text)
 
 		m.client.SendMock.Expect(wantMessage).Return(nilMessage, assert.AnError)
 
 		// act
-		err := m.publisher.PublishPost(
-			article.source,
-			article.title,
-			article.url,
-			article.channels,
-			article.tags,
-		)
+		err := m.publisher.PublishPost(post)
 
 		// assert
 		require.Error(t, err)
 		require.ErrorAs(t, assert.AnError, &err)
 	})
 }
+
+func TestFormatMessage(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		post Post
+		want string
+	}{
+		{
+			name: "base",
+			post: Post{Source: "Go", Title: "Title", URL: "https://go.dev/blog/post"},
+			want: "Go: [Title](https://go.dev/blog/post)",
+		},
+		{
+			name: "tags",
+			post: Post{Source: "Go", Title: "Title", URL: "https://go.dev/blog/post", Tags: []string{"go", "#news"}},
+			want: "Go: [Title](https://go.dev/blog/post)\n#go, #news\n",
+		},
+		{
+			name: "canonical url",
+			post: Post{
+				Source:       "Go",
+				Title:        "Title",
+				URL:          "https://go.dev/blog/post?utm_source=rss",
+				CanonicalURL: "https://go.dev/blog/post",
+			},
+			want: "Go: [Title](https://go.dev/blog/post)",
+		},
+		{
+			name: "description",
+			post: Post{Source: "Go", Title: "Title", URL: "https://go.dev/blog/post", Description: " Use *go_test* "},
+			want: "Go: [Title](https://go.dev/blog/post)\n\nUse \\*go\\_test\\*",
+		},
+		{
+			name: "description and tags",
+			post: Post{Source: "Go", Title: "Title", URL: "https://go.dev/blog/post", Description: "About", Tags: []string{"go"}},
+			want: "Go: [Title](https://go.dev/blog/post)\n\nAbout\n#go\n",
+		},
+		{
+			name: "long description",
+			post: Post{Source: "Go", Title: "Title", URL: "https://go.dev/blog/post", Description: strings.Repeat("я", maxDescriptionLength+1)},
+			want: "Go: [Title](https://go.dev/blog/post)\n\n" + strings.Repeat("я", maxDescriptionLength) + "…",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got := formatMessage(tc.post)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPublishAlert(t *testing.T) {
+	t.Parallel()
+
+	alert := Alert{Source: "Go", URL: "https://go.dev/blog/", Reason: "no cards matched in 3 consecutive crawls"}
+
+	cases := []struct {
+		name string
+		chat string
+		want tgbotapi.MessageConfig
+	}{
+		{
+			name: "channel",
+			chat: "admins",
+			want: tgbotapi.NewMessageToChannel("@admins", formatAlert(alert)),
+		},
+		{
+			name: "chat id",
+			chat: "-100123",
+			want: tgbotapi.NewMessage(-100123, formatAlert(alert)),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, cleanup := newMk(t)
+			defer cleanup()
+
+			m.client.SendMock.Expect(tc.want).Return(nilMessage, nilError)
+
+			// act
+			err := m.publisher.PublishAlert(tc.chat, alert)
+
+			// assert
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFormatAlert(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		alert Alert
+		want  string
+	}{
+		{
+			name: "last good crawl",
+			alert: Alert{
+				Source:   "Go",
+				URL:      "https://go.dev/blog/",
+				Reason:   "no cards matched in 3 consecutive crawls",
+				LastGood: time.Date(2023, time.November, 20, 10, 30, 0, 0, time.UTC),
+			},
+			want: "Source Go looks broken: no cards matched in 3 consecutive crawls\nhttps://go.dev/blog/\nLast good crawl: 2023-11-20 10:30:00 UTC",
+		},
+		{
+			name:  "unknown last good crawl",
+			alert: Alert{Source: "Go", URL: "https://go.dev/blog/", Reason: "no cards matched in 3 consecutive crawls"},
+			want:  "Source Go looks broken: no cards matched in 3 consecutive crawls\nhttps://go.dev/blog/\nLast good crawl: unknown",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			got := formatAlert(tc.alert)
+
+			// assert
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
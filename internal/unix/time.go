@@ -5,3 +5,21 @@ import "time"
 func TimeNow() int64 {
 	return time.Now().UnixNano()
 }
+
+// FromTime переводит время в формат хранения. Нулевое время
+// соответствует нулю, а не отрицательному значению.
+func FromTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// ToTime переводит время из формата хранения. Ноль соответствует
+// нулевому времени.
+func ToTime(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, v).UTC()
+}